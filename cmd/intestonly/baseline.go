@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// fingerprint identifies a finding independently of its position, so that
+// a baseline survives edits that shift lines around
+type fingerprint struct {
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// baseline is the set of accepted findings
+type baseline map[fingerprint]bool
+
+// fingerprintOf returns the fingerprint of a finding. Files inside the
+// working directory are stored relative to it, so the baseline can be
+// shared between checkouts.
+func fingerprintOf(f finding) fingerprint {
+	file, ok := relToWorkDir(f.File)
+	if !ok {
+		file = filepath.ToSlash(f.File)
+	}
+	return fingerprint{File: file, Message: f.Message}
+}
+
+// readBaseline decodes a baseline written by writeBaseline
+func readBaseline(r io.Reader) (baseline, error) {
+	var fingerprints []fingerprint
+	if err := json.NewDecoder(r).Decode(&fingerprints); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %w", err)
+	}
+
+	b := make(baseline, len(fingerprints))
+	for _, fp := range fingerprints {
+		b[fp] = true
+	}
+	return b, nil
+}
+
+// loadBaseline reads the baseline file at path
+func loadBaseline(path string) (baseline, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readBaseline(file)
+}
+
+// writeBaseline writes the fingerprints of the findings to w as a sorted
+// JSON list
+func writeBaseline(w io.Writer, findings []finding) error {
+	fingerprints := []fingerprint{}
+	seen := make(baseline)
+	for _, f := range findings {
+		fp := fingerprintOf(f)
+		if seen[fp] {
+			continue
+		}
+		seen[fp] = true
+		fingerprints = append(fingerprints, fp)
+	}
+
+	sort.Slice(fingerprints, func(i, j int) bool {
+		if fingerprints[i].File != fingerprints[j].File {
+			return fingerprints[i].File < fingerprints[j].File
+		}
+		return fingerprints[i].Message < fingerprints[j].Message
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fingerprints)
+}
+
+// saveBaseline writes the baseline file at path
+func saveBaseline(path string, findings []finding) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := writeBaseline(file, findings); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// filter returns the findings that are not in the baseline
+func (b baseline) filter(findings []finding) []finding {
+	var fresh []finding
+	for _, f := range findings {
+		if !b[fingerprintOf(f)] {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBaselineMatch(t *testing.T) {
+	b, err := readBaseline(strings.NewReader(`[
+		{"file": "/src/p/p.go", "message": "identifier \"helper\" is only used in test files but is not part of test files"}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to read baseline: %s", err)
+	}
+
+	// The baselined finding moved to another line but is still suppressed
+	moved := testFindings[0]
+	moved.Line += 10
+
+	fresh := b.filter([]finding{moved, testFindings[1]})
+	if len(fresh) != 1 || fresh[0] != testFindings[1] {
+		t.Errorf("Expected only the finding missing from the baseline, got %v", fresh)
+	}
+}
+
+func TestBaselineMiss(t *testing.T) {
+	b, err := readBaseline(strings.NewReader(`[
+		{"file": "/src/p/other.go", "message": "identifier \"helper\" is only used in test files but is not part of test files"},
+		{"file": "/src/p/q.go", "message": "identifier \"other\" is only used in test files but is not part of test files"}
+	]`))
+	if err != nil {
+		t.Fatalf("Failed to read baseline: %s", err)
+	}
+
+	if fresh := b.filter(testFindings); len(fresh) != len(testFindings) {
+		t.Errorf("Expected no findings to be suppressed, got %v", fresh)
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	// Duplicates collapse into a single fingerprint
+	findings := append([]finding{testFindings[1]}, testFindings...)
+
+	var buf bytes.Buffer
+	if err := writeBaseline(&buf, findings); err != nil {
+		t.Fatalf("Failed to write baseline: %s", err)
+	}
+
+	b, err := readBaseline(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read written baseline: %s\n%s", err, buf.String())
+	}
+	if len(b) != len(testFindings) {
+		t.Errorf("Expected %d fingerprints, got %d", len(testFindings), len(b))
+	}
+	if fresh := b.filter(testFindings); len(fresh) != 0 {
+		t.Errorf("Expected all findings to be suppressed, got %v", fresh)
+	}
+}
+
+func TestBaselineInvalid(t *testing.T) {
+	if _, err := readBaseline(strings.NewReader(`{"file": "p.go"}`)); err == nil {
+		t.Error("Expected an error for a baseline that is not a list")
+	}
+}
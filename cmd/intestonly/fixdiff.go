@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// fileEdit is a suggested text edit with its position resolved to byte
+// offsets in the file
+type fileEdit struct {
+	File    string
+	Start   int
+	End     int
+	NewText string
+}
+
+// writeFixDiff writes a unified diff of the edits to w, one file at a time.
+// The files are only read, never changed.
+func writeFixDiff(w io.Writer, edits []fileEdit) error {
+	byFile := make(map[string][]fileEdit)
+	for _, e := range edits {
+		byFile[e.File] = append(byFile[e.File], e)
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		name, ok := relToWorkDir(file)
+		if !ok {
+			name = filepath.ToSlash(file)
+		}
+		if _, err := io.WriteString(w, unifiedDiff(name, content, byFile[file])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineChange replaces the lines [start, end) of a file with lines
+type lineChange struct {
+	start, end int
+	lines      []string
+}
+
+// unifiedDiff returns the unified diff of applying edits to content. Edits
+// that repeat or overlap an earlier edit are dropped.
+func unifiedDiff(name string, content []byte, edits []fileEdit) string {
+	lines := splitLines(string(content))
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line)
+	}
+	// lineOf returns the index of the line holding the byte at offset
+	lineOf := func(offset int) int {
+		return sort.Search(len(lines), func(i int) bool { return offsets[i+1] > offset })
+	}
+
+	edits = append([]fileEdit(nil), edits...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].Start < edits[j].Start })
+
+	// Each change keeps its new text up to resume, the offset where the
+	// original content continues
+	var changes []lineChange
+	var texts []string
+	var resumes []int
+	last := -1
+	for _, e := range edits {
+		if e.Start < last || e.Start > e.End || e.End > len(content) {
+			continue
+		}
+		last = e.End
+
+		start := lineOf(e.Start)
+		end := start + 1
+		if e.End > e.Start {
+			end = lineOf(e.End-1) + 1
+		}
+		end = min(end, len(lines))
+
+		// Merge edits that touch the same lines
+		if n := len(changes); n > 0 && changes[n-1].end > start {
+			texts[n-1] += string(content[resumes[n-1]:e.Start]) + e.NewText
+			resumes[n-1] = e.End
+			changes[n-1].end = max(changes[n-1].end, end)
+			continue
+		}
+		changes = append(changes, lineChange{start: start, end: end})
+		texts = append(texts, string(content[offsets[start]:e.Start])+e.NewText)
+		resumes = append(resumes, e.End)
+	}
+	for i := range changes {
+		changes[i].lines = splitLines(texts[i] + string(content[resumes[i]:offsets[changes[i].end]]))
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", name, name)
+	delta := 0
+	for i := 0; i < len(changes); {
+		// Group the changes whose context overlaps into one hunk
+		j := i + 1
+		for j < len(changes) && changes[j].start-changes[j-1].end <= 2*diffContext {
+			j++
+		}
+		from := max(changes[i].start-diffContext, 0)
+		to := min(changes[j-1].end+diffContext, len(lines))
+
+		var body bytes.Buffer
+		oldCount, newCount := 0, 0
+		pos := from
+		for _, c := range changes[i:j] {
+			for ; pos < c.start; pos++ {
+				writeDiffLine(&body, ' ', lines[pos])
+				oldCount++
+				newCount++
+			}
+			for ; pos < c.end; pos++ {
+				writeDiffLine(&body, '-', lines[pos])
+				oldCount++
+			}
+			for _, line := range c.lines {
+				writeDiffLine(&body, '+', line)
+				newCount++
+			}
+		}
+		for ; pos < to; pos++ {
+			writeDiffLine(&body, ' ', lines[pos])
+			oldCount++
+			newCount++
+		}
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n", hunkRange(from, oldCount), hunkRange(from+delta, newCount))
+		buf.Write(body.Bytes())
+		delta += newCount - oldCount
+		i = j
+	}
+	return buf.String()
+}
+
+// hunkRange formats the line range of a hunk that starts at the zero-based
+// line start. An empty range names the line before it, as diff does.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// writeDiffLine writes a line of a hunk, marking a missing final newline
+func writeDiffLine(buf *bytes.Buffer, prefix byte, line string) {
+	buf.WriteByte(prefix)
+	buf.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		buf.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// splitLines splits s into lines that keep their line terminators
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// suggestedEdits returns the text edits of the suggested fixes of diag
+func suggestedEdits(fset *token.FileSet, diag analysis.Diagnostic) []fileEdit {
+	var edits []fileEdit
+	for _, fix := range diag.SuggestedFixes {
+		for _, edit := range fix.TextEdits {
+			start := fset.Position(edit.Pos)
+			end := start
+			if edit.End.IsValid() {
+				end = fset.Position(edit.End)
+			}
+			edits = append(edits, fileEdit{
+				File:    start.Filename,
+				Start:   start.Offset,
+				End:     end.Offset,
+				NewText: string(edit.NewText),
+			})
+		}
+	}
+	return edits
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const fixDiffSource = `package p
+
+func Run() {}
+
+// helper is only called by tests
+func helper() int {
+	return 1
+}
+
+func Stop() {}
+`
+
+func TestWriteFixDiff(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "p.go")
+	if err := os.WriteFile(file, []byte(fixDiffSource), 0o600); err != nil {
+		t.Fatalf("Failed to write source: %s", err)
+	}
+
+	// The edit suggested for helper, reported twice by the package and its
+	// test variant
+	start := strings.Index(fixDiffSource, "// helper")
+	end := strings.Index(fixDiffSource, "}\n\nfunc Stop") + 1
+	edit := fileEdit{File: file, Start: start, End: end}
+
+	var buf bytes.Buffer
+	if err := writeFixDiff(&buf, []fileEdit{edit, edit}); err != nil {
+		t.Fatalf("Failed to write diff: %s", err)
+	}
+
+	name := filepath.ToSlash(file)
+	want := "--- a/" + name + "\n+++ b/" + name + "\n" +
+		"@@ -2,9 +2,6 @@\n" +
+		" \n" +
+		" func Run() {}\n" +
+		" \n" +
+		"-// helper is only called by tests\n" +
+		"-func helper() int {\n" +
+		"-\treturn 1\n" +
+		"-}\n" +
+		"+\n" +
+		" \n" +
+		" func Stop() {}\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read source: %s", err)
+	}
+	if string(content) != fixDiffSource {
+		t.Errorf("The source file was changed:\n%s", content)
+	}
+}
+
+func TestUnifiedDiffNoEdits(t *testing.T) {
+	if diff := unifiedDiff("p.go", []byte(fixDiffSource), nil); diff != "" {
+		t.Errorf("Expected no diff, got:\n%s", diff)
+	}
+}
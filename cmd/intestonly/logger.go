@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// jsonLogger writes debug output of the analyzer as JSON lines
+type jsonLogger struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newJSONLogger(w io.Writer) *jsonLogger {
+	return &jsonLogger{enc: json.NewEncoder(w)}
+}
+
+// logEntry is a single line of debug output
+type logEntry struct {
+	Time    string `json:"time"`
+	Level   string `json:"level"`
+	Message string `json:"msg"`
+}
+
+func (l *jsonLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Debug output is best effort
+	_ = l.enc.Encode(logEntry{
+		Time:    time.Now().UTC().Format(time.RFC3339Nano),
+		Level:   "debug",
+		Message: fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newJSONLogger(&buf)
+	logger.Debugf("Found %d declarations", 3)
+	logger.Debugf("Skipping package %s", "example.com/p")
+
+	dec := json.NewDecoder(&buf)
+	var messages []string
+	for dec.More() {
+		var entry logEntry
+		if err := dec.Decode(&entry); err != nil {
+			t.Fatalf("Output is not valid JSON lines: %s", err)
+		}
+		if entry.Level != "debug" || entry.Time == "" {
+			t.Errorf("Unexpected entry %+v", entry)
+		}
+		messages = append(messages, entry.Message)
+	}
+
+	if len(messages) != 2 || messages[0] != "Found 3 declarations" || messages[1] != "Skipping package example.com/p" {
+		t.Errorf("Unexpected messages %q", messages)
+	}
+}
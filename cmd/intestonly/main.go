@@ -2,9 +2,11 @@ package main
 
 import (
 	"flag"
-	"fmt"
+	"go/ast"
+	"go/token"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/korchasa/golangci-intestonly/pkg/golinters/intestonly"
 	"golang.org/x/tools/go/analysis"
@@ -13,50 +15,204 @@ import (
 )
 
 // Main entry point for the intestonly analyzer
-// Usage: go run ./cmd/intestonly/main.go ./...
+// Usage: go run ./cmd/intestonly/main.go [-format text|json|sarif] [-max-issues n] [-debug] [-fix-diff] [-only-exported] [analyzer flags] [-baseline file [-write-baseline]] ./...
 func main() {
 	log.SetPrefix("intestonly: ")
 	log.SetFlags(0)
 
+	format := flag.String("format", formatText, "output format: text, json or sarif")
+	baselineFile := flag.String("baseline", "", "suppress the findings listed in this baseline file")
+	updateBaseline := flag.Bool("write-baseline", false, "write the current findings to the -baseline file instead of reporting them")
+	maxIssues := flag.Int("max-issues", 0, "succeed as long as there are at most this many test-only declarations")
+	debug := flag.Bool("debug", false, "write debug output of the analysis to stderr as JSON lines")
+	fixDiff := flag.Bool("fix-diff", false, "print the suggested fixes as a unified diff instead of the findings, without changing any file")
+	onlyExported := flag.Bool("only-exported", false, "only report exported declarations")
+
+	// The analyzer flags, such as -library-mode, configure the analysis
+	config := intestonly.DefaultConfig()
+	analyzer := intestonly.NewAnalyzer(config)
+	analyzer.Flags.VisitAll(func(f *flag.Flag) {
+		flag.Var(f.Value, f.Name, f.Usage)
+	})
 	flag.Parse()
 	args := flag.Args()
 
 	if len(args) == 0 {
 		log.Fatalf("No packages specified")
 	}
-
-	// Load the packages
-	cfg := &packages.Config{
-		Mode:  packages.LoadAllSyntax,
-		Tests: true,
+	if *format != formatText && *format != formatJSON && *format != formatSARIF {
+		log.Fatalf("Unknown output format %q", *format)
+	}
+	if *updateBaseline && *baselineFile == "" {
+		log.Fatalf("-write-baseline requires -baseline")
+	}
+	if *maxIssues < 0 {
+		log.Fatalf("-max-issues must not be negative")
 	}
 
-	pkgs, err := packages.Load(cfg, args...)
+	// Load the packages
+	pkgs, err := loadPackages("", args)
 	if err != nil {
 		log.Fatalf("Failed to load packages: %v", err)
 	}
 
 	// Run the analyzer
-	results, err := checker.Analyze([]*analysis.Analyzer{intestonly.Analyzer}, pkgs, nil)
+	if *debug {
+		config.Logger = newJSONLogger(os.Stderr)
+	}
+	results, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil)
 	if err != nil {
 		log.Fatalf("Error running analyzer: %v", err)
 	}
 
+	// Collect results
+	findings, edits, failed := collectFindings(results, *onlyExported)
+
+	// Record or apply the baseline
+	if *updateBaseline {
+		if err := saveBaseline(*baselineFile, findings); err != nil {
+			log.Fatalf("Failed to write baseline: %v", err)
+		}
+		os.Exit(exitStatus(0, 0, failed))
+	}
+	if *baselineFile != "" {
+		accepted, err := loadBaseline(*baselineFile)
+		if err != nil {
+			log.Fatalf("Failed to read baseline: %v", err)
+		}
+		findings = accepted.filter(findings)
+	}
+
 	// Print results
-	exitCode := 0
+	if *fixDiff {
+		var all []fileEdit
+		for _, f := range findings {
+			all = append(all, edits[f]...)
+		}
+		if err := writeFixDiff(os.Stdout, all); err != nil {
+			log.Fatalf("Failed to print fixes: %v", err)
+		}
+	} else if err := render(os.Stdout, *format, findings); err != nil {
+		log.Fatalf("Failed to print results: %v", err)
+	}
+	testOnly := countTestOnly(findings, analyzer.Name)
+	log.Printf("%d test-only declarations found", testOnly)
+
+	os.Exit(exitStatus(testOnly, *maxIssues, failed))
+}
+
+// exitStatus returns the exit code of a run: 1 when the analysis failed or
+// found more than maxIssues test-only declarations, 0 otherwise
+func exitStatus(testOnly, maxIssues int, failed bool) int {
+	if failed || testOnly > maxIssues {
+		return 1
+	}
+	return 0
+}
+
+// countTestOnly returns the number of findings that report test-only
+// declarations. Their category is the analyzer name, optionally followed
+// by the visibility of the declarations, while reports of unused
+// declarations and informational notes have categories of their own.
+func countTestOnly(findings []finding, analyzer string) int {
+	count := 0
+	for _, f := range findings {
+		if f.Category == analyzer || strings.HasPrefix(f.Category, analyzer+"-") {
+			count++
+		}
+	}
+	return count
+}
+
+// loadPackages loads the packages matching patterns in dir, or in the
+// working directory when dir is empty, together with their tests. Patterns
+// resolve through go.work workspaces and replace directives like they do
+// for the go command.
+func loadPackages(dir string, patterns []string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode:  packages.LoadAllSyntax | packages.NeedModule,
+		Dir:   dir,
+		Tests: true,
+	}
+	return packages.Load(cfg, patterns...)
+}
+
+// collectFindings returns the diagnostics of the analyzed packages with the
+// edits of their suggested fixes, only those of exported declarations when
+// onlyExported is set, and each of them once. failed is true when the
+// analysis of a package failed.
+func collectFindings(results *checker.Graph, onlyExported bool) (findings []finding, edits map[finding][]fileEdit, failed bool) {
+	edits = make(map[finding][]fileEdit)
 	for _, act := range results.Roots {
 		if act.Err != nil {
 			log.Printf("Error analyzing %s: %v", act.Package.ID, act.Err)
-			exitCode = 1
+			failed = true
 			continue
 		}
 
 		for _, diag := range act.Diagnostics {
+			if onlyExported && !reportsExported(act.Package, diag) {
+				continue
+			}
 			pos := act.Package.Fset.Position(diag.Pos)
-			fmt.Printf("%s: %s\n", pos, diag.Message)
-			exitCode = 1
+			category := diag.Category
+			if category == "" {
+				category = act.Analyzer.Name
+			}
+			f := finding{
+				File:     pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Message:  diag.Message,
+				Category: category,
+			}
+			// A package and its test variant report the same declarations
+			// of their shared files
+			if _, seen := edits[f]; seen {
+				continue
+			}
+			edits[f] = suggestedEdits(act.Package.Fset, diag)
+			findings = append(findings, f)
 		}
 	}
+	return findings, edits, failed
+}
+
+// reportsExported returns true if the diagnostic reports an exported
+// declaration. A file-level report, which isn't positioned at a declared
+// name, reports the declarations of its related locations and counts when
+// one of them is exported.
+func reportsExported(pkg *packages.Package, diag analysis.Diagnostic) bool {
+	if name := identAt(pkg, diag.Pos); name != "" {
+		return ast.IsExported(name)
+	}
+	for _, related := range diag.Related {
+		if ast.IsExported(identAt(pkg, related.Pos)) {
+			return true
+		}
+	}
+	return false
+}
 
-	os.Exit(exitCode)
+// identAt returns the name of the identifier starting at pos in the syntax
+// of the package, or an empty string if there is none
+func identAt(pkg *packages.Package, pos token.Pos) string {
+	for _, file := range pkg.Syntax {
+		if pos < file.FileStart || pos > file.FileEnd {
+			continue
+		}
+
+		var name string
+		ast.Inspect(file, func(n ast.Node) bool {
+			if name != "" || n == nil || pos < n.Pos() || pos >= n.End() {
+				return false
+			}
+			if ident, ok := n.(*ast.Ident); ok && ident.Pos() == pos {
+				name = ident.Name
+			}
+			return true
+		})
+		return name
+	}
+	return ""
 }
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/korchasa/golangci-intestonly/pkg/golinters/intestonly"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		findings  int
+		maxIssues int
+		failed    bool
+		want      int
+	}{
+		{findings: 0, maxIssues: 0, want: 0},
+		{findings: 1, maxIssues: 0, want: 1},
+		{findings: 5, maxIssues: 5, want: 0},
+		{findings: 6, maxIssues: 5, want: 1},
+		{findings: 0, maxIssues: 5, failed: true, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := exitStatus(tt.findings, tt.maxIssues, tt.failed); got != tt.want {
+			t.Errorf("exitStatus(%d, %d, %v) = %d, want %d", tt.findings, tt.maxIssues, tt.failed, got, tt.want)
+		}
+	}
+}
+
+func TestCountTestOnly(t *testing.T) {
+	findings := []finding{
+		{Category: "intestonly"},
+		{Category: "intestonly-exported"},
+		{Category: "intestonly-unexported"},
+		{Category: "unused"},
+		{Category: "excluded"},
+		{Category: "intestonlyx"},
+	}
+	if got := countTestOnly(findings, "intestonly"); got != 3 {
+		t.Errorf("countTestOnly() = %d, want 3", got)
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	// Workspaces don't allow -mod=mod, which the environment may set
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "")
+
+	dir := filepath.Join("..", "..", "testdata", "workspace")
+	pkgs, err := loadPackages(dir, []string{"./app/...", "./lib/..."})
+	if err != nil {
+		t.Fatalf("Failed to load packages: %s", err)
+	}
+	for _, pkg := range pkgs {
+		for _, err := range pkg.Errors {
+			t.Errorf("Failed to load %s: %s", pkg.ID, err)
+		}
+	}
+
+	results, err := checker.Analyze([]*analysis.Analyzer{intestonly.Analyzer}, pkgs, nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze packages: %s", err)
+	}
+	findings, _, failed := collectFindings(results, false)
+	if failed {
+		t.Fatal("The analysis failed")
+	}
+
+	// Both modules are analyzed with their tests, and lib resolves through
+	// the workspace and the replace directive of app
+	var got []string
+	for _, f := range findings {
+		got = append(got, filepath.Base(filepath.Dir(f.File))+"/"+filepath.Base(f.File)+": "+f.Message)
+	}
+	sort.Strings(got)
+	want := []string{
+		`app/app.go: identifier "banner" is only used in test files but is not part of test files`,
+		`lib/lib.go: identifier "reverse" is only used in test files but is not part of test files`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// loadTestdata loads a package of the GOPATH-style testdata directory with
+// its tests
+func loadTestdata(t *testing.T, pkg string) []*packages.Package {
+	t.Helper()
+
+	testdata, err := filepath.Abs(filepath.Join("..", "..", "testdata"))
+	if err != nil {
+		t.Fatalf("Failed to resolve testdata: %s", err)
+	}
+	t.Setenv("GOPATH", testdata)
+	t.Setenv("GO111MODULE", "off")
+	t.Setenv("GOFLAGS", "")
+	t.Setenv("GOWORK", "off")
+
+	pkgs, err := loadPackages(filepath.Join(testdata, "src", pkg), []string{"."})
+	if err != nil {
+		t.Fatalf("Failed to load packages: %s", err)
+	}
+	return pkgs
+}
+
+func TestPackageVariants(t *testing.T) {
+	pkgs := loadTestdata(t, "unused")
+	config := intestonly.DefaultConfig()
+	config.ReportUnusedEverywhere = true
+	results, err := checker.Analyze([]*analysis.Analyzer{intestonly.NewAnalyzer(config)}, pkgs, nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze packages: %s", err)
+	}
+	if len(results.Roots) < 2 {
+		t.Fatalf("Expected the package and its test variant, got %d packages", len(results.Roots))
+	}
+	findings, _, failed := collectFindings(results, false)
+	if failed {
+		t.Fatal("The analysis failed")
+	}
+
+	// The package built without its tests sees onlyTested as unused, and
+	// both variants see neverCalled
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Category+": "+f.Message)
+	}
+	sort.Strings(got)
+	want := []string{
+		`intestonly: identifier "onlyTested" is only used in test files but is not part of test files`,
+		`unused: identifier "neverCalled" is never used`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnnotatedExclusions(t *testing.T) {
+	pkgs := loadTestdata(t, "methodrefs")
+
+	// The flags as the command line passes them on to the analyzer
+	analyzer := intestonly.NewAnalyzer(intestonly.DefaultConfig())
+	if err := analyzer.Flags.Parse([]string{"-consider-reflection-risky", "-annotate-exclusions"}); err != nil {
+		t.Fatalf("Failed to parse flags: %s", err)
+	}
+	results, err := checker.Analyze([]*analysis.Analyzer{analyzer}, pkgs, nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze packages: %s", err)
+	}
+	findings, _, failed := collectFindings(results, false)
+	if failed {
+		t.Fatal("The analysis failed")
+	}
+
+	// The test-only exported methods are skipped as reflection-risky and
+	// only noted, which doesn't fail the run
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Category+": "+f.Message)
+	}
+	sort.Strings(got)
+	want := []string{
+		`excluded: identifier "Close" would be reported but is excluded as reflection-risky`,
+		`excluded: identifier "Inspect" would be reported but is excluded as reflection-risky`,
+		`excluded: identifier "Reset" would be reported but is excluded as reflection-risky`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	testOnly := countTestOnly(findings, analyzer.Name)
+	if testOnly != 0 || exitStatus(testOnly, 0, failed) != 0 {
+		t.Errorf("Expected the exclusion notes to not count as test-only declarations, got %d", testOnly)
+	}
+}
+
+func TestReportsExported(t *testing.T) {
+	src := `package lib
+
+func Parse(input string) string { return normalize(input) }
+
+func normalize(input string) string { return input }
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "lib.go", src, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	pkg := &packages.Package{Fset: fset, Syntax: []*ast.File{file}}
+	pos := func(name string) token.Pos {
+		return file.Pos() + token.Pos(strings.Index(src, "func "+name)+len("func "))
+	}
+	related := func(names ...string) []analysis.RelatedInformation {
+		var infos []analysis.RelatedInformation
+		for _, name := range names {
+			infos = append(infos, analysis.RelatedInformation{Pos: pos(name)})
+		}
+		return infos
+	}
+
+	tests := []struct {
+		name string
+		diag analysis.Diagnostic
+		want bool
+	}{
+		{"exported", analysis.Diagnostic{Pos: pos("Parse")}, true},
+		{"unexported", analysis.Diagnostic{Pos: pos("normalize")}, false},
+		{"file with an exported declaration", analysis.Diagnostic{Pos: file.Package, Related: related("normalize", "Parse")}, true},
+		{"file without exported declarations", analysis.Diagnostic{Pos: file.Package, Related: related("normalize")}, false},
+		{"position outside the package", analysis.Diagnostic{Pos: token.NoPos}, false},
+	}
+	for _, tt := range tests {
+		if got := reportsExported(pkg, tt.diag); got != tt.want {
+			t.Errorf("%s: reportsExported() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Output formats supported by the -format flag
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatSARIF = "sarif"
+)
+
+// finding is a diagnostic with its position resolved
+type finding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Message  string `json:"message"`
+	Category string `json:"category"`
+}
+
+// render writes the findings to w in the given format, sorted by position
+// so that the output of a run doesn't depend on the order of the packages
+func render(w io.Writer, format string, findings []finding) error {
+	sortFindings(findings)
+
+	switch format {
+	case formatText:
+		for _, f := range findings {
+			if _, err := fmt.Fprintf(w, "%s:%d:%d: %s\n", f.File, f.Line, f.Column, f.Message); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatJSON:
+		// Always emit an array, even when there is nothing to report
+		if findings == nil {
+			findings = []finding{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	case formatSARIF:
+		return writeSARIF(w, findings)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// sortFindings sorts the findings by file, line, column and message
+func sortFindings(findings []finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Message < b.Message
+	})
+}
+
+// relToWorkDir returns the slash-separated path of file relative to the
+// working directory, if the file is inside it
+func relToWorkDir(file string) (string, bool) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(wd, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
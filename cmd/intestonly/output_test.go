@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+var testFindings = []finding{
+	{File: "/src/p/p.go", Line: 5, Column: 6, Message: "identifier \"helper\" is only used in test files but is not part of test files", Category: "intestonly"},
+	{File: "/src/p/q.go", Line: 12, Column: 2, Message: "identifier \"limit\" is only used in test files but is not part of test files", Category: "intestonly"},
+}
+
+func TestRenderText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render(&buf, formatText, testFindings); err != nil {
+		t.Fatalf("Failed to render: %s", err)
+	}
+
+	want := "/src/p/p.go:5:6: identifier \"helper\" is only used in test files but is not part of test files\n" +
+		"/src/p/q.go:12:2: identifier \"limit\" is only used in test files but is not part of test files\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render(&buf, formatJSON, testFindings); err != nil {
+		t.Fatalf("Failed to render: %s", err)
+	}
+
+	var got []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Output is not valid JSON: %s\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 findings, got %d", len(got))
+	}
+	for _, field := range []string{"file", "line", "column", "message", "category"} {
+		if _, ok := got[0][field]; !ok {
+			t.Errorf("Field %q is missing in %v", field, got[0])
+		}
+	}
+	if got[1]["line"] != float64(12) || got[1]["category"] != "intestonly" {
+		t.Errorf("Unexpected second finding: %v", got[1])
+	}
+}
+
+func TestRenderSorted(t *testing.T) {
+	findings := []finding{
+		{File: "/src/p/q.go", Line: 3, Column: 1, Message: "b"},
+		{File: "/src/p/p.go", Line: 10, Column: 6, Message: "c"},
+		{File: "/src/p/p.go", Line: 9, Column: 6, Message: "d"},
+		{File: "/src/p/q.go", Line: 3, Column: 1, Message: "a"},
+		{File: "/src/p/p.go", Line: 9, Column: 2, Message: "e"},
+	}
+	var buf bytes.Buffer
+	if err := render(&buf, formatText, findings); err != nil {
+		t.Fatalf("Failed to render: %s", err)
+	}
+
+	want := "/src/p/p.go:9:2: e\n" +
+		"/src/p/p.go:9:6: d\n" +
+		"/src/p/p.go:10:6: c\n" +
+		"/src/p/q.go:3:1: a\n" +
+		"/src/p/q.go:3:1: b\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestRenderJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := render(&buf, formatJSON, nil); err != nil {
+		t.Fatalf("Failed to render: %s", err)
+	}
+	if got := bytes.TrimSpace(buf.Bytes()); string(got) != "[]" {
+		t.Errorf("Expected an empty array, got %s", got)
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if err := render(&bytes.Buffer{}, "xml", testFindings); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
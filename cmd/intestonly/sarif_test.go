@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, testFindings); err != nil {
+		t.Fatalf("Failed to write SARIF: %s", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %s\n%s", err, buf.String())
+	}
+
+	if log.Version != "2.1.0" {
+		t.Errorf("Unexpected SARIF version %q", log.Version)
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("Expected 1 run, got %d", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name != "intestonly" || len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "intestonly" {
+		t.Errorf("Unexpected tool description: %+v", run.Tool)
+	}
+	if len(run.Results) != len(testFindings) {
+		t.Fatalf("Expected %d results, got %d", len(testFindings), len(run.Results))
+	}
+
+	result := run.Results[1]
+	if result.RuleID != "intestonly" || len(result.Locations) != 1 {
+		t.Fatalf("Unexpected result: %+v", result)
+	}
+	location := result.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "file:///src/p/q.go" || location.Region.StartLine != 12 {
+		t.Errorf("Unexpected location: %+v", location)
+	}
+}
+
+func TestWriteSARIFEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSARIF(&buf, nil); err != nil {
+		t.Fatalf("Failed to write SARIF: %s", err)
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []json.RawMessage `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Output is not valid JSON: %s\n%s", err, buf.String())
+	}
+	if len(log.Runs) != 1 || log.Runs[0].Results == nil || len(log.Runs[0].Results) != 0 {
+		t.Errorf("Expected a single run with an empty results array, got:\n%s", buf.String())
+	}
+}
+
+func TestSARIFURIRelative(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get wd: %s", err)
+	}
+
+	if got := sarifURI(filepath.Join(wd, "sub", "file.go")); got != "sub/file.go" {
+		t.Errorf("Expected a path relative to the working directory, got %q", got)
+	}
+}
@@ -0,0 +1,88 @@
+package intestonly
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
+)
+
+// Issue is a declaration reported by the analyzer, with its position
+// resolved
+type Issue struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// String formats the issue as file:line:column: message
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+// AnalyzeDir analyzes the packages in dir and below it together with their
+// tests, and returns the issues sorted by position. A nil config uses
+// DefaultConfig.
+func AnalyzeDir(dir string, config *Config) ([]Issue, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	cfg := &packages.Config{
+		Mode:  packages.LoadAllSyntax,
+		Dir:   dir,
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %w", err)
+	}
+	var loadErr error
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		if loadErr == nil && len(pkg.Errors) > 0 {
+			loadErr = fmt.Errorf("failed to load %s: %w", pkg.ID, pkg.Errors[0])
+		}
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{NewAnalyzer(config)}, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// A package and its test variant may report the same declaration
+	seen := make(map[Issue]bool)
+	var issues []Issue
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			return nil, fmt.Errorf("failed to analyze %s: %w", act.Package.ID, act.Err)
+		}
+
+		for _, diag := range act.Diagnostics {
+			pos := act.Package.Fset.Position(diag.Pos)
+			issue := Issue{File: pos.Filename, Line: pos.Line, Column: pos.Column, Message: diag.Message}
+			if !seen[issue] {
+				seen[issue] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return issues, nil
+}
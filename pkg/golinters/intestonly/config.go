@@ -0,0 +1,280 @@
+package intestonly
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+)
+
+// Config controls which detection strategies the analyzer applies.
+type Config struct {
+	// EnableReflectionAnalysis treats names passed as string constants to
+	// reflect's MethodByName and FieldByName as usages of the declarations
+	// with those names. It is the master switch of all reflection handling:
+	// when off, ConsiderReflectionRisky has no effect either.
+	EnableReflectionAnalysis bool
+
+	// ExampleFunctionsCountAsProduction treats identifiers referenced in
+	// ExampleXxx functions of test files as production usages, since
+	// examples are published as part of the package documentation.
+	ExampleFunctionsCountAsProduction bool
+
+	// BenchmarksCountAsProduction treats identifiers referenced in
+	// BenchmarkXxx functions of test files as production usages, for teams
+	// that only benchmark code which is meant to ship.
+	BenchmarksCountAsProduction bool
+
+	// TreatTestdataAsTests treats files under a testdata directory as test
+	// fixtures: their declarations are never reported and their references
+	// count as test usages. It is off by default because packages under
+	// testdata are only analyzed when requested explicitly, e.g. by
+	// analysistest.
+	TreatTestdataAsTests bool
+
+	// RespectBuildTags skips files that the default build context excludes
+	// by file name suffix or build constraint, for drivers that load
+	// packages with other build tags or for another platform.
+	RespectBuildTags bool
+
+	// TreatLinkedFunctionsAsUsed skips functions without a body, which are
+	// implemented in assembly, and functions named by a //go:linkname
+	// directive in their doc comment, which other packages or cgo may call.
+	TreatLinkedFunctionsAsUsed bool
+
+	// SkipGeneratedFiles never reports declarations of files with the
+	// standard "// Code generated ... DO NOT EDIT." header, such as protobuf
+	// code or mocks. Their usages of other declarations still count.
+	SkipGeneratedFiles bool
+
+	// OverrideIsTestFiles lists additional patterns of test files, matched
+	// against both the base name and the full slash-separated path. Patterns
+	// use path.Match wildcards (*, ?, [...]); a pattern without wildcards
+	// matches any name containing it.
+	OverrideIsTestFiles []string
+
+	// OverrideIsProductionFiles lists patterns of files that hold
+	// production code, such as shipped *_example.go files, matched like
+	// OverrideIsTestFiles. They take precedence over OverrideIsTestFiles and
+	// TreatTestdataAsTests, but _test.go files are always tests.
+	OverrideIsProductionFiles []string
+
+	// IgnoreFiles lists files whose declarations are never reported, by
+	// base name or by a slash-separated path matching the end of their
+	// path, such as "internal/compat.go". Unlike files of
+	// OverrideIsTestFiles, their references still count as production
+	// usages of the other declarations; unlike OverrideIsProductionFiles,
+	// their own declarations aren't checked at all.
+	IgnoreFiles []string
+
+	// LibraryMode never reports exported declarations, which are part of
+	// the public API of a library even when only its tests use them yet.
+	// Unexported declarations are still checked.
+	LibraryMode bool
+
+	// SkipPackages lists import paths of packages that are not analyzed at
+	// all. A path matches its own package and every package below it;
+	// paths with wildcards are matched with path.Match.
+	SkipPackages []string
+
+	// IncludePatterns restricts the report to declarations whose name
+	// matches one of these patterns; an empty list checks every
+	// declaration. Patterns with wildcards use path.Match, and a pattern
+	// without wildcards matches whole camelCase words of the name, so "api"
+	// matches APIClient but not Capitalize.
+	IncludePatterns []string
+
+	// ExcludePatterns lists patterns of declaration names that are never
+	// reported, matched like IncludePatterns. They apply after
+	// IncludePatterns.
+	ExcludePatterns []string
+
+	// AlwaysUsed lists the exact names of declarations that are used in
+	// production in ways the analyzer can't see, such as plugin entry
+	// points. They are never reported, and the types of their fields and
+	// embedded types count as used in production.
+	AlwaysUsed []string
+
+	// TestHelperPatterns lists patterns of names that mark declarations as
+	// test helpers, which are never reported, in addition to the words
+	// such as "mock", "fake" or "setup". They are matched like
+	// IncludePatterns.
+	TestHelperPatterns []string
+
+	// ExplicitTestCases lists the exact names of declarations that are
+	// checked even though they are named like test helpers, such as
+	// setupDatabase.
+	ExplicitTestCases []string
+
+	// ReportExplicitTestCases reports the ExplicitTestCases without looking
+	// at their usages. It is meant for test fixtures of the analyzer, whose
+	// expected reports must also appear when a package is analyzed without
+	// its tests.
+	ReportExplicitTestCases bool
+
+	// KnownImplicitMethods lists method signatures, such as
+	// "String() string", that are called implicitly through standard
+	// interfaces. Methods matching one by name and signature are never
+	// reported.
+	KnownImplicitMethods []string
+
+	// ConsiderReflectionRisky skips declarations that reflection may reach
+	// through names built at run time, which the reflection analysis can't
+	// follow: exported methods and names matching ReflectionRiskPatterns.
+	ConsiderReflectionRisky bool
+
+	// ReflectionRiskPatterns lists the wildcard patterns of risky names
+	// used when ConsiderReflectionRisky is set.
+	ReflectionRiskPatterns []string
+
+	// AnnotateExclusions reports the declarations that ConsiderReflectionRisky
+	// keeps from being reported as only used in tests with an informational
+	// diagnostic of the "excluded" category, to tell them apart from dead
+	// code.
+	AnnotateExclusions bool
+
+	// ReportUnusedEverywhere also reports unexported declarations that
+	// neither tests nor production code use, with the "unused" category.
+	// They are only reported for packages with _test.go files, by the test
+	// variant of the package.
+	ReportUnusedEverywhere bool
+
+	// CollapseFileLevelReports reports a file whose declarations are all
+	// only used in tests with a single diagnostic instead of one per
+	// declaration.
+	CollapseFileLevelReports bool
+
+	// EnableStringLiteralAnalysis treats a declared name followed by an
+	// opening parenthesis inside a string literal of production code, such
+	// as a template, as a usage of that declaration.
+	EnableStringLiteralAnalysis bool
+
+	// StringLiteralTestUsages also counts such mentions in string literals
+	// of test files as test usages.
+	StringLiteralTestUsages bool
+
+	// EnableStructTagAnalysis extends the string literal analysis to the
+	// values of struct tags, where any declared name counts as a usage,
+	// e.g. MyValidator in `validate:"custom=MyValidator"`. It requires
+	// EnableStringLiteralAnalysis.
+	EnableStructTagAnalysis bool
+
+	// EnableGoGenerateAnalysis treats declared names that appear as
+	// arguments of //go:generate directives as usages, such as an interface
+	// that mockgen reads. Directives in production files count as
+	// production usages.
+	EnableGoGenerateAnalysis bool
+
+	// StringReferenceMinLength is the length a declared name needs for the
+	// string literal analysis to match it. Short names such as "id" appear
+	// in ordinary text too often.
+	StringReferenceMinLength int
+
+	// PackageOverrides maps import path patterns, matched like
+	// SkipPackages, to settings that replace the options above for the
+	// matching packages. Options an override leaves unset keep their values.
+	PackageOverrides map[string]IntestOnlySettings
+
+	// VerifyAgainstTypesInfo drops the report of a declaration that the type
+	// checker sees used in production code outside its own declaration,
+	// e.g. in the fields of a type that is only used in tests, and logs it
+	// instead. It trades detections for fewer false positives.
+	VerifyAgainstTypesInfo bool
+
+	// CategoriesByVisibility reports test-only declarations with the
+	// category "intestonly-exported" or "intestonly-unexported", so tools
+	// such as golangci-lint can give them different severities. By default
+	// the reports have no category of their own.
+	CategoriesByVisibility bool
+
+	// MessageTemplate is the text/template of the message reported for
+	// test-only declarations, with {{.Kind}} (function, method, type,
+	// constant or variable) and {{.Name}} available. Empty uses
+	// DefaultMessageTemplate.
+	MessageTemplate string
+
+	// Logger receives debug output of the analysis. Nil discards it.
+	Logger Logger
+}
+
+// DefaultConfig returns the configuration used by Analyzer.
+func DefaultConfig() *Config {
+	return &Config{
+		EnableReflectionAnalysis:   true,
+		TreatLinkedFunctionsAsUsed: true,
+		SkipGeneratedFiles:         true,
+		KnownImplicitMethods:       append([]string(nil), defaultKnownImplicitMethods...),
+		ReflectionRiskPatterns:     append([]string(nil), defaultReflectionRiskPatterns...),
+		StringReferenceMinLength:   3,
+		MessageTemplate:            DefaultMessageTemplate,
+	}
+}
+
+// Validate checks that the configuration is self-consistent: that its
+// patterns are well-formed, that no file pattern marks files as both tests
+// and production code, and that the message template renders. The analyzer
+// validates the configuration of each package before analyzing it.
+func (c *Config) Validate() error {
+	overridden := make([]string, 0, len(c.PackageOverrides))
+	for pattern := range c.PackageOverrides {
+		overridden = append(overridden, pattern)
+	}
+	sort.Strings(overridden)
+
+	fields := []struct {
+		name     string
+		patterns []string
+	}{
+		{"OverrideIsTestFiles", c.OverrideIsTestFiles},
+		{"OverrideIsProductionFiles", c.OverrideIsProductionFiles},
+		{"SkipPackages", c.SkipPackages},
+		{"IncludePatterns", c.IncludePatterns},
+		{"ExcludePatterns", c.ExcludePatterns},
+		{"TestHelperPatterns", c.TestHelperPatterns},
+		{"ReflectionRiskPatterns", c.ReflectionRiskPatterns},
+		{"PackageOverrides", overridden},
+	}
+	for _, field := range fields {
+		if err := validatePatterns(field.name, field.patterns); err != nil {
+			return err
+		}
+	}
+
+	for _, pattern := range c.OverrideIsTestFiles {
+		if slices.Contains(c.OverrideIsProductionFiles, pattern) {
+			return fmt.Errorf("pattern %q is in both OverrideIsTestFiles and OverrideIsProductionFiles", pattern)
+		}
+	}
+
+	if c.StringReferenceMinLength < 0 {
+		return fmt.Errorf("StringReferenceMinLength is negative: %d", c.StringReferenceMinLength)
+	}
+
+	if _, err := parseMessageTemplate(c.MessageTemplate); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validatePatterns checks that the wildcard patterns of a field are
+// well-formed. An empty pattern has no wildcards and so matches everything.
+func validatePatterns(field string, patterns []string) error {
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("%s: empty pattern", field)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s: malformed pattern %q", field, pattern)
+		}
+	}
+	return nil
+}
+
+// logger returns the configured logger, or one that discards everything
+func (c *Config) logger() Logger {
+	if c.Logger == nil {
+		return nopLogger{}
+	}
+	return c.Logger
+}
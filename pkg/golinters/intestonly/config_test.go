@@ -0,0 +1,80 @@
+package intestonly
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		good    func(*Config)
+		bad     func(*Config)
+		wantErr string
+	}{
+		{
+			name:    "malformed pattern",
+			good:    func(c *Config) { c.IncludePatterns = []string{"[Pp]arse*"} },
+			bad:     func(c *Config) { c.IncludePatterns = []string{"[Pp]arse*", "[parse"} },
+			wantErr: `IncludePatterns: malformed pattern "[parse"`,
+		},
+		{
+			name:    "empty pattern",
+			good:    func(c *Config) { c.OverrideIsTestFiles = []string{"_fixture.go"} },
+			bad:     func(c *Config) { c.OverrideIsTestFiles = []string{""} },
+			wantErr: "OverrideIsTestFiles: empty pattern",
+		},
+		{
+			name: "malformed package override",
+			good: func(c *Config) {
+				c.PackageOverrides = map[string]IntestOnlySettings{"example.com/*/mocks": {}}
+			},
+			bad: func(c *Config) {
+				c.PackageOverrides = map[string]IntestOnlySettings{"example.com/[app": {}}
+			},
+			wantErr: `PackageOverrides: malformed pattern "example.com/[app"`,
+		},
+		{
+			name: "test and production file",
+			good: func(c *Config) {
+				c.OverrideIsTestFiles = []string{"*_fixture.go"}
+				c.OverrideIsProductionFiles = []string{"*_example.go"}
+			},
+			bad: func(c *Config) {
+				c.OverrideIsTestFiles = []string{"*_fixture.go", "*_example.go"}
+				c.OverrideIsProductionFiles = []string{"*_example.go"}
+			},
+			wantErr: `pattern "*_example.go" is in both OverrideIsTestFiles and OverrideIsProductionFiles`,
+		},
+		{
+			name:    "negative minimum length",
+			good:    func(c *Config) { c.StringReferenceMinLength = 0 },
+			bad:     func(c *Config) { c.StringReferenceMinLength = -1 },
+			wantErr: "StringReferenceMinLength is negative: -1",
+		},
+		{
+			name:    "invalid message template",
+			good:    func(c *Config) { c.MessageTemplate = "{{.Kind}} {{.Name}} is only used in tests" },
+			bad:     func(c *Config) { c.MessageTemplate = "{{.Name" },
+			wantErr: "invalid message template",
+		},
+	}
+
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("The default config is invalid: %s", err)
+	}
+	for _, tt := range tests {
+		good := DefaultConfig()
+		tt.good(good)
+		if err := good.Validate(); err != nil {
+			t.Errorf("%s: unexpected error for the good config: %s", tt.name, err)
+		}
+
+		bad := DefaultConfig()
+		tt.bad(bad)
+		err := bad.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Validate() = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
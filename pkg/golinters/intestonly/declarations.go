@@ -0,0 +1,163 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+type intestOnlyInfo struct {
+	obj       types.Object // Declared object, which usages resolve to
+	pos       token.Pos
+	name      string
+	filePath  string
+	generated bool // Declared in a file with a generated code header
+	ignored   bool // Declared in a file with an //intestonly:file-ignore directive
+	isMethod  bool
+	node      ast.Node            // Declaring FuncDecl, TypeSpec or ValueSpec
+	genDecl   *ast.GenDecl        // Top-level declaration enclosing a TypeSpec or ValueSpec
+	comments  []*ast.CommentGroup // Doc and same-line comments, checked for directives
+}
+
+// collectDeclarations collects the declarations of non-test files, keyed by
+// the objects they declare, so that methods of the same name on different
+// types are told apart
+func collectDeclarations(fset *token.FileSet, typesInfo *types.Info, files []*ast.File, testFiles *testFileCache) map[types.Object]intestOnlyInfo {
+	decls := make(map[types.Object]intestOnlyInfo) // All declarations in non-test files
+	genDecls := make(map[ast.Spec]*ast.GenDecl)    // Top-level declarations enclosing each spec
+
+	// add records the declaration of the object named by ident
+	add := func(ident *ast.Ident, info intestOnlyInfo) {
+		obj := typesInfo.Defs[ident]
+		if obj == nil {
+			return
+		}
+		info.obj = obj
+		decls[obj] = info
+	}
+
+	for _, file := range files {
+		fileName := fset.File(file.Pos()).Name()
+		isTest := testFiles.isTestFile(fileName)
+
+		// Skip test helper files even if they're not test files
+		if shouldIgnoreFile(fileName, testFiles.config) {
+			continue
+		}
+
+		if !isTest {
+			lineComments := commentsByLine(fset, file)
+			generated := ast.IsGenerated(file)
+			ignored := hasFileIgnoreDirective(file)
+
+			// declComments returns the comments that may hold directives
+			// for a declaration named at pos
+			declComments := func(pos token.Pos, groups ...*ast.CommentGroup) []*ast.CommentGroup {
+				return append(groups, lineComments[fset.Position(pos).Line]...)
+			}
+
+			for _, decl := range file.Decls {
+				if genDecl, ok := decl.(*ast.GenDecl); ok {
+					for _, spec := range genDecl.Specs {
+						genDecls[spec] = genDecl
+					}
+				}
+			}
+
+			ast.Inspect(file, func(node ast.Node) bool {
+				switch n := node.(type) {
+				case *ast.FuncDecl:
+					// Declarations in function bodies are local and can't
+					// be used elsewhere, so they aren't collected
+					if n.Name != nil && n.Name.Name != "" {
+						name := n.Name.Name
+
+						// Skip test helper identifiers unless they're explicit test cases
+						if isTestHelper(name, testFiles.config) && !isExplicitTestCase(name, testFiles.config) {
+							return false
+						}
+
+						// Functions and methods (functions with receivers)
+						add(n.Name, intestOnlyInfo{
+							pos:       n.Name.Pos(),
+							name:      name,
+							filePath:  fileName,
+							generated: generated,
+							ignored:   ignored,
+							isMethod:  n.Recv != nil && len(n.Recv.List) > 0,
+							node:      n,
+							comments:  declComments(n.Name.Pos(), n.Doc),
+						})
+					}
+					return false
+				case *ast.TypeSpec:
+					if n.Name != nil && n.Name.Name != "" {
+						name := n.Name.Name
+
+						// Skip test helper identifiers unless they're explicit test cases
+						if isTestHelper(name, testFiles.config) && !isExplicitTestCase(name, testFiles.config) {
+							return false
+						}
+
+						add(n.Name, intestOnlyInfo{
+							pos:       n.Name.Pos(),
+							name:      name,
+							filePath:  fileName,
+							generated: generated,
+							ignored:   ignored,
+							isMethod:  false,
+							node:      n,
+							genDecl:   genDecls[n],
+							comments:  declComments(n.Name.Pos(), n.Doc, n.Comment, genDeclDoc(genDecls[n])),
+						})
+					}
+					return false
+				case *ast.ValueSpec:
+					for _, name := range n.Names {
+						if name != nil && name.Name != "" {
+							// Skip test helper identifiers unless they're explicit test cases
+							if isTestHelper(name.Name, testFiles.config) && !isExplicitTestCase(name.Name, testFiles.config) {
+								continue
+							}
+
+							add(name, intestOnlyInfo{
+								pos:       name.Pos(),
+								name:      name.Name,
+								filePath:  fileName,
+								generated: generated,
+								ignored:   ignored,
+								isMethod:  false,
+								node:      n,
+								genDecl:   genDecls[n],
+								comments:  declComments(name.Pos(), n.Doc, n.Comment, genDeclDoc(genDecls[n])),
+							})
+						}
+					}
+					// Initializers may hold function literals with locals
+					return false
+				}
+				return true
+			})
+		}
+	}
+
+	return decls
+}
+
+// declsByName indexes the declared objects by name, for references that
+// only name a declaration, such as those in string literals
+func declsByName(decls map[types.Object]intestOnlyInfo) map[string][]types.Object {
+	byName := make(map[string][]types.Object)
+	for obj, info := range decls {
+		byName[info.name] = append(byName[info.name], obj)
+	}
+	return byName
+}
+
+// genDeclDoc returns the doc comment of a declaration group, if any
+func genDeclDoc(genDecl *ast.GenDecl) *ast.CommentGroup {
+	if genDecl == nil {
+		return nil
+	}
+	return genDecl.Doc
+}
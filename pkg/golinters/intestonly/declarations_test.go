@@ -0,0 +1,108 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func parseFiles(t *testing.T, sources map[string]string) (*token.FileSet, []*ast.File) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range sources {
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %s", name, err)
+		}
+		files = append(files, file)
+	}
+	return fset, files
+}
+
+// declarationsByName type checks the files and collects their declarations,
+// keyed by name
+func declarationsByName(t *testing.T, fset *token.FileSet, files []*ast.File) map[string]intestOnlyInfo {
+	t.Helper()
+
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object)}
+	if _, err := (&types.Config{}).Check("p", fset, files, info); err != nil {
+		t.Fatalf("Failed to type check: %s", err)
+	}
+
+	decls := make(map[string]intestOnlyInfo)
+	for _, decl := range collectDeclarations(fset, info, files, newTestFileCache(DefaultConfig())) {
+		decls[decl.name] = decl
+	}
+	return decls
+}
+
+func TestCollectDeclarationsNodes(t *testing.T) {
+	src := `package p
+
+func function() int {
+	return 1
+}
+
+func (t T) method() {}
+
+type T struct {
+	field int
+}
+
+var (
+	value = 1
+)
+
+const constant = "c"
+`
+	fset, files := parseFiles(t, map[string]string{"p.go": src})
+	decls := declarationsByName(t, fset, files)
+
+	// The node of each declaration spans its whole source, which the
+	// suggested fixes delete; specs also keep their enclosing declaration
+	tests := []struct {
+		name    string
+		source  string
+		genDecl bool
+	}{
+		{"function", "func function() int {\n\treturn 1\n}", false},
+		{"method", "func (t T) method() {}", false},
+		{"T", "T struct {\n\tfield int\n}", true},
+		{"value", "value = 1", true},
+		{"constant", `constant = "c"`, true},
+	}
+	for _, tt := range tests {
+		info, ok := decls[tt.name]
+		if !ok {
+			t.Errorf("Declaration %s was not collected", tt.name)
+			continue
+		}
+		start, end := fset.Position(info.node.Pos()).Offset, fset.Position(info.node.End()).Offset
+		if got := src[start:end]; got != tt.source {
+			t.Errorf("Unexpected span for %s: %q, want %q", tt.name, got, tt.source)
+		}
+		if (info.genDecl != nil) != tt.genDecl {
+			t.Errorf("Unexpected enclosing declaration for %s: %v", tt.name, info.genDecl)
+		}
+	}
+}
+
+func TestCollectDeclarationsFileIgnore(t *testing.T) {
+	fset, files := parseFiles(t, map[string]string{
+		"ignored.go": "//intestonly:file-ignore\n\npackage p\n\nfunc ignored() {}\n",
+		"late.go":    "package p\n\n//intestonly:file-ignore\n\nfunc late() {}\n",
+	})
+	decls := declarationsByName(t, fset, files)
+
+	// Only a directive above the package clause covers the whole file
+	if !decls["ignored"].ignored {
+		t.Error("Expected the declarations of ignored.go to be ignored")
+	}
+	if decls["late"].ignored {
+		t.Error("Expected a directive below the package clause to not ignore the file")
+	}
+}
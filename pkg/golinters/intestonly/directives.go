@@ -0,0 +1,151 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode"
+)
+
+// ignoreDirective suppresses the report for the declaration it is attached to
+const ignoreDirective = "//intestonly:ignore"
+
+// fileIgnoreDirective in the comments above the package clause suppresses
+// the reports of every declaration in the file
+const fileIgnoreDirective = "//intestonly:file-ignore"
+
+// hasFileIgnoreDirective returns true if a comment above the package clause
+// of the file is an //intestonly:file-ignore directive
+func hasFileIgnoreDirective(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, fileIgnoreDirective) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isIgnored returns true if a directive of the declaration or of its file
+// suppresses its report
+func isIgnored(info intestOnlyInfo) bool {
+	return info.ignored || hasIgnoreDirective(info.comments)
+}
+
+// commentsByLine indexes the comment groups of a file by the line they start on
+func commentsByLine(fset *token.FileSet, file *ast.File) map[int][]*ast.CommentGroup {
+	byLine := make(map[int][]*ast.CommentGroup)
+	for _, group := range file.Comments {
+		line := fset.Position(group.Pos()).Line
+		byLine[line] = append(byLine[line], group)
+	}
+	return byLine
+}
+
+// hasIgnoreDirective returns true if any of the comments is an
+// //intestonly:ignore directive or a //nolint directive covering this linter
+func hasIgnoreDirective(groups []*ast.CommentGroup) bool {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		// CommentGroup.Text drops directives, so look at the raw comments
+		for _, comment := range group.List {
+			if strings.HasPrefix(comment.Text, ignoreDirective) || isNolintForLinter(comment.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNolintForLinter returns true for //nolint and for //nolint lists that
+// include intestonly or all
+func isNolintForLinter(text string) bool {
+	text = strings.TrimSpace(strings.TrimPrefix(text, "//"))
+	if !strings.HasPrefix(text, "nolint") {
+		return false
+	}
+
+	rest := strings.TrimPrefix(text, "nolint")
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		// A bare //nolint applies to every linter
+		return true
+	}
+	if rest[0] != ':' {
+		return false
+	}
+
+	// The linter list ends at the first space, e.g. "//nolint:a,b // reason"
+	list := strings.Fields(rest[1:])
+	if len(list) == 0 {
+		return false
+	}
+	for _, linter := range strings.Split(list[0], ",") {
+		if linter == "intestonly" || linter == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// generateDirective runs a command on go generate
+const generateDirective = "//go:generate "
+
+// goGenerateReferences calls record for each declared name that is an
+// argument of a //go:generate directive of the file, such as the interface
+// in "//go:generate mockgen -destination=mock.go . Store". Arguments are
+// split at white space and at commas, which separate lists of names.
+func goGenerateReferences(file *ast.File, declared map[string][]types.Object, record func(name string, pos token.Pos)) {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, generateDirective) {
+				continue
+			}
+			args := strings.FieldsFunc(strings.TrimPrefix(comment.Text, generateDirective), func(r rune) bool {
+				return r == ',' || unicode.IsSpace(r)
+			})
+			for _, arg := range args {
+				if _, ok := declared[arg]; ok {
+					record(arg, comment.Pos())
+				}
+			}
+		}
+	}
+}
+
+// linknameDirective links a function to a symbol of another package
+const linknameDirective = "//go:linkname "
+
+// isLinkedFunction returns true for a function without a body, which is
+// implemented in assembly or linked from elsewhere, and for a function
+// named by a //go:linkname directive in its doc comment. Such functions are
+// called in ways that Go code doesn't show.
+func isLinkedFunction(info intestOnlyInfo) bool {
+	fn, ok := info.node.(*ast.FuncDecl)
+	if !ok {
+		return false
+	}
+	if fn.Body == nil {
+		return true
+	}
+	if fn.Doc == nil {
+		return false
+	}
+
+	for _, comment := range fn.Doc.List {
+		if !strings.HasPrefix(comment.Text, linknameDirective) {
+			continue
+		}
+		// The first argument is the local name of the linked function
+		if fields := strings.Fields(strings.TrimPrefix(comment.Text, linknameDirective)); len(fields) > 0 && fields[0] == fn.Name.Name {
+			return true
+		}
+	}
+	return false
+}
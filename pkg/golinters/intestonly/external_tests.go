@@ -0,0 +1,86 @@
+package intestonly
+
+import (
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// unusedFact marks an exported declaration that nothing in its package
+// uses. The black-box tests of a package (package foo_test) are type
+// checked as a separate package that imports it, so the package can't see
+// their usages; it passes its unused declarations on to them instead, and
+// the black-box test package reports those it uses.
+type unusedFact struct {
+	Kind string // function, method, type, constant or variable
+}
+
+func (*unusedFact) AFact() {}
+
+func (f *unusedFact) String() string {
+	return "unused " + f.Kind
+}
+
+// exportUnused passes an unused declaration on to the black-box test
+// package. Only exported declarations can be used there.
+func exportUnused(pass *analysis.Pass, info intestOnlyInfo) {
+	if !token.IsExported(info.name) {
+		return
+	}
+	pass.ExportObjectFact(info.obj, &unusedFact{Kind: declKind(info)})
+}
+
+// reportBlackBoxUsages reports the declarations of the package under test
+// that are only used by this black-box test package, and returns how many
+// it reported. It does nothing for other packages.
+func reportBlackBoxUsages(pass *analysis.Pass, testFiles *testFileCache, config *Config, message *template.Template) (int, error) {
+	tested, ok := strings.CutSuffix(pass.Pkg.Path(), "_test")
+	if !ok {
+		return 0, nil
+	}
+
+	// The first usage of each declaration of the package under test
+	usages := make(map[types.Object]token.Pos)
+	for ident, obj := range pass.TypesInfo.Uses {
+		if obj.Pkg() == nil || obj.Pkg().Path() != tested {
+			continue
+		}
+		if fn, ok := obj.(*types.Func); ok {
+			obj = fn.Origin()
+		}
+		if !testFiles.isTestFile(pass.Fset.File(ident.Pos()).Name()) {
+			continue
+		}
+		if pos, seen := usages[obj]; !seen || ident.Pos() < pos {
+			usages[obj] = ident.Pos()
+		}
+	}
+
+	objs := make([]types.Object, 0, len(usages))
+	for obj := range usages {
+		objs = append(objs, obj)
+	}
+	sort.Slice(objs, func(i, j int) bool { return objs[i].Pos() < objs[j].Pos() })
+
+	reported := 0
+	for _, obj := range objs {
+		var fact unusedFact
+		if !pass.ImportObjectFact(obj, &fact) {
+			continue
+		}
+
+		msg, err := renderMessage(message, messageData{Kind: fact.Kind, Name: obj.Name()})
+		if err != nil {
+			return reported, err
+		}
+		info := intestOnlyInfo{obj: obj, pos: obj.Pos(), name: obj.Name()}
+		reportTestOnly(pass, info, msg, testOnlyCategory(obj.Name(), config), usages[obj])
+		reported++
+	}
+
+	return reported, nil
+}
@@ -0,0 +1,175 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// isTestFile returns true if the file holds test code: a _test.go file, a
+// file matching one of OverrideIsTestFiles, or a file under a testdata
+// directory when TreatTestdataAsTests is set. Files matching
+// OverrideIsProductionFiles are production code unless they are _test.go
+// files.
+func isTestFile(filename string, config *Config) bool {
+	if strings.HasSuffix(filename, "_test.go") {
+		return true
+	}
+
+	if matchesFile(filename, config.OverrideIsProductionFiles) {
+		return false
+	}
+
+	if config.TreatTestdataAsTests && inTestdata(filename) {
+		return true
+	}
+
+	return matchesFile(filename, config.OverrideIsTestFiles)
+}
+
+// matchesFile reports whether the base name or the full slash-separated
+// path of the file matches any of the patterns
+func matchesFile(filename string, patterns []string) bool {
+	return matchesPattern(filepath.Base(filename), patterns) || matchesPattern(filepath.ToSlash(filename), patterns)
+}
+
+// testFileCache memoizes isTestFile for the files of one run, during which
+// the configuration doesn't change
+type testFileCache struct {
+	config  *Config
+	results map[string]bool
+}
+
+// newTestFileCache returns an empty cache for the given configuration
+func newTestFileCache(config *Config) *testFileCache {
+	return &testFileCache{config: config, results: make(map[string]bool)}
+}
+
+// isTestFile returns the cached result of isTestFile for the file
+func (c *testFileCache) isTestFile(filename string) bool {
+	isTest, ok := c.results[filename]
+	if !ok {
+		isTest = isTestFile(filename, c.config)
+		c.results[filename] = isTest
+	}
+	return isTest
+}
+
+// matchesBuildContext reports whether the go tool builds the file in the
+// default build context, judging by its name and build constraints. The
+// constraints come from the parsed file rather than from disk, which may
+// hold another version of the file or none at all.
+func matchesBuildContext(filename string, file *ast.File) bool {
+	if !strings.HasSuffix(filename, ".go") {
+		return true
+	}
+
+	ctxt := build.Default
+	ctxt.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(buildHeader(file))), nil
+	}
+	match, err := ctxt.MatchFile(filepath.Dir(filename), filepath.Base(filename))
+	return err != nil || match
+}
+
+// buildHeader returns a file header with the build constraints of the file,
+// which is all of the file that build.Context.MatchFile reads
+func buildHeader(file *ast.File) string {
+	var header strings.Builder
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if constraint.IsGoBuild(comment.Text) || constraint.IsPlusBuild(comment.Text) {
+				header.WriteString(comment.Text + "\n\n")
+			}
+		}
+	}
+	header.WriteString("package " + file.Name.Name + "\n")
+	return header.String()
+}
+
+// matchWildcard reports whether s matches a glob pattern with path.Match
+// semantics: * matches any run of characters except /, ? matches a single
+// one and [...] a character class. A pattern without wildcards matches any
+// s containing it.
+func matchWildcard(pattern, s string) bool {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.Contains(s, pattern)
+	}
+
+	matched, err := path.Match(pattern, s)
+	return err == nil && matched
+}
+
+// inTestdata returns true if any directory in the path is named testdata
+func inTestdata(filename string) bool {
+	for _, dir := range strings.Split(filepath.ToSlash(filepath.Dir(filename)), "/") {
+		if dir == "testdata" {
+			return true
+		}
+	}
+	return false
+}
+
+// shouldIgnoreFile returns true if the declarations of the file are never
+// reported: files named like test helpers and the files of IgnoreFiles
+func shouldIgnoreFile(filename string, config *Config) bool {
+	// Ignore files that are named like test helpers
+	base := filepath.Base(filename)
+	if strings.Contains(base, "test_helper") ||
+		strings.Contains(base, "test_util") ||
+		strings.Contains(base, "testutil") ||
+		strings.Contains(base, "testhelper") {
+		return true
+	}
+
+	slashed := filepath.ToSlash(filename)
+	for _, name := range config.IgnoreFiles {
+		if name == base || slashed == name || strings.HasSuffix(slashed, "/"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPattern reports whether s matches any of the patterns with
+// matchWildcard
+func matchesPattern(s string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matchWildcard(pattern, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSkippedPackage reports whether the package with the given import path
+// is excluded by SkipPackages
+func isSkippedPackage(pkgPath string, config *Config) bool {
+	for _, pattern := range config.SkipPackages {
+		if matchesPackage(pattern, pkgPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPackage reports whether an import path pattern covers pkgPath. A
+// plain path matches its own package and every package below it; a path
+// with wildcards is matched with path.Match.
+func matchesPackage(pattern, pkgPath string) bool {
+	if strings.ContainsAny(pattern, "*?[") {
+		matched, err := path.Match(pattern, pkgPath)
+		return err == nil && matched
+	}
+
+	pattern = strings.TrimSuffix(pattern, "/")
+	return pkgPath == pattern || strings.HasPrefix(pkgPath, pattern+"/")
+}
@@ -0,0 +1,199 @@
+package intestonly
+
+import "testing"
+
+func TestIsTestFile(t *testing.T) {
+	testdataAsTests := DefaultConfig()
+	testdataAsTests.TreatTestdataAsTests = true
+	additional := DefaultConfig()
+	additional.OverrideIsTestFiles = []string{"*_fixture.go", "testdata/"}
+
+	tests := []struct {
+		name     string
+		filename string
+		config   *Config
+		want     bool
+	}{
+		{"test file", "/x/p/p_test.go", DefaultConfig(), true},
+		{"production file", "/x/p/p.go", DefaultConfig(), false},
+		{"testdata by default", "/x/testdata/y.go", DefaultConfig(), false},
+		{"testdata as tests", "/x/testdata/y.go", testdataAsTests, true},
+		{"nested testdata as tests", "/x/testdata/src/p/y.go", testdataAsTests, true},
+		{"testdata prefix is not testdata", "/x/testdata_old/y.go", testdataAsTests, false},
+		{"file named testdata", "/x/p/testdata.go", testdataAsTests, false},
+		{"additional pattern on the base name", "/x/p/db_fixture.go", additional, true},
+		{"additional pattern on the path", "/x/testdata/y.go", additional, true},
+		{"no additional pattern matches", "/x/p/fixture.go", additional, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTestFile(tt.filename, tt.config); got != tt.want {
+				t.Errorf("isTestFile(%q) = %v, want %v", tt.filename, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTestFileOverride(t *testing.T) {
+	config := DefaultConfig()
+	config.OverrideIsTestFiles = []string{"*_mock_*.go", "integration_??.go", "/fixtures/"}
+
+	tests := map[string]bool{
+		"/x/p/db_mock_gen.go":         true,
+		"/x/p/db_mock.go":             false,
+		"/x/p/integration_01.go":      true,
+		"/x/p/integration_001.go":     false,
+		"/x/fixtures/p/data.go":       true,
+		"/x/p/fixtures.go":            false,
+		"/x/p/production.go":          false,
+		"/x/p/production_mock_x.go":   true,
+		"/x/p/sub/production_mock.go": false,
+	}
+
+	for filename, want := range tests {
+		if got := isTestFile(filename, config); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", filename, got, want)
+		}
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"mock", "db_mock.go", true},
+		{"mock", "db.go", false},
+		{"*_test.go", "p_test.go", true},
+		{"*_test.go", "p.go", false},
+		{"gen_*", "gen_types.go", true},
+		{"gen_*", "types_gen.go", false},
+		{"*_mock_*.go", "db_mock_gen.go", true},
+		{"*_mock_*.go", "db_mock.go", false},
+		{"integration_??_test.go", "integration_01_test.go", true},
+		{"integration_??_test.go", "integration_1_test.go", false},
+		{"*mock*", "a_mock_b", true},
+		{"*.go", "dir/file.go", false},
+		{"*/mocks/*.go", "internal/mocks/db.go", true},
+		{"file_[ab].go", "file_a.go", true},
+		{"file_[ab].go", "file_c.go", false},
+		{"[", "[", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchWildcard(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchWildcard(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestIsSkippedPackage(t *testing.T) {
+	config := DefaultConfig()
+	config.SkipPackages = []string{"example.com/app/mocks", "example.com/gen/*/client"}
+
+	tests := map[string]bool{
+		"example.com/app/mocks":          true,
+		"example.com/app/mocks/db":       true,
+		"example.com/app/mocksupport":    false,
+		"example.com/app":                false,
+		"example.com/gen/users/client":   true,
+		"example.com/gen/users/client/x": false,
+		"example.com/gen/client":         false,
+	}
+
+	for pkgPath, want := range tests {
+		if got := isSkippedPackage(pkgPath, config); got != want {
+			t.Errorf("isSkippedPackage(%q) = %v, want %v", pkgPath, got, want)
+		}
+	}
+}
+
+func TestIsTestFileProductionOverride(t *testing.T) {
+	config := DefaultConfig()
+	config.TreatTestdataAsTests = true
+	config.OverrideIsTestFiles = []string{"*_example*.go", "/fixtures/"}
+	config.OverrideIsProductionFiles = []string{"*_example.go", "/fixtures/shipped/", "*_test.go"}
+
+	// Production patterns win over test patterns, whatever their order
+	tests := map[string]bool{
+		"/x/p/api_example.go":                  false,
+		"/x/p/api_example_gen.go":              true,
+		"/x/fixtures/shipped/data.go":          false,
+		"/x/fixtures/other/data.go":            true,
+		"/x/testdata/shipped/data.go":          true,
+		"/x/testdata/fixtures/shipped/data.go": false,
+		"/x/p/p_test.go":                       true,
+	}
+
+	for filename, want := range tests {
+		if got := isTestFile(filename, config); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", filename, got, want)
+		}
+	}
+}
+
+func TestTestFileCache(t *testing.T) {
+	mocks := DefaultConfig()
+	mocks.OverrideIsTestFiles = []string{"*_mock.go"}
+
+	// Each run has its own cache, so a changed configuration is honored
+	for _, tt := range []struct {
+		config *Config
+		want   bool
+	}{{DefaultConfig(), false}, {mocks, true}, {DefaultConfig(), false}} {
+		cache := newTestFileCache(tt.config)
+		for i := 0; i < 2; i++ {
+			if got := cache.isTestFile("/x/p/db_mock.go"); got != tt.want {
+				t.Errorf("isTestFile() = %v, want %v with %q", got, tt.want, tt.config.OverrideIsTestFiles)
+			}
+		}
+	}
+}
+
+// BenchmarkIsTestFile checks the files of the identifiers of a package, many
+// of which share a file, as productionInterfaces does
+func BenchmarkIsTestFile(b *testing.B) {
+	config := DefaultConfig()
+	config.OverrideIsTestFiles = []string{"*_mock.go", "*/fixtures/*.go", "gen_*"}
+	files := []string{"/x/p/a.go", "/x/p/b.go", "/x/p/a_test.go", "/x/p/db_mock.go"}
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < 1000; j++ {
+				isTestFile(files[j%len(files)], config)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cache := newTestFileCache(config)
+			for j := 0; j < 1000; j++ {
+				cache.isTestFile(files[j%len(files)])
+			}
+		}
+	})
+}
+
+func TestShouldIgnoreFile(t *testing.T) {
+	config := DefaultConfig()
+	config.IgnoreFiles = []string{"compat.go", "internal/legacy/api.go"}
+
+	tests := []struct {
+		filename string
+		want     bool
+	}{
+		{"/src/app/compat.go", true},
+		{"/src/app/internal/legacy/api.go", true},
+		{"/src/app/test_helpers.go", true},
+		{"/src/app/api.go", false},
+		{"/src/app/mycompat.go", false},
+		{"/src/app/notinternal/legacy/api.go", false},
+	}
+	for _, tt := range tests {
+		if got := shouldIgnoreFile(tt.filename, config); got != tt.want {
+			t.Errorf("shouldIgnoreFile(%q) = %v, want %v", tt.filename, got, tt.want)
+		}
+	}
+}
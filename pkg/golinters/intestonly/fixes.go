@@ -0,0 +1,108 @@
+package intestonly
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// deletionFix returns a fix that removes the declaration together with its
+// doc comment, or nil if it can't be removed without touching other
+// declarations
+func deletionFix(info intestOnlyInfo) []analysis.SuggestedFix {
+	var pos, end token.Pos
+
+	switch n := info.node.(type) {
+	case *ast.FuncDecl:
+		pos, end = n.Pos(), n.End()
+		if n.Doc != nil {
+			pos = n.Doc.Pos()
+		}
+	case ast.Spec:
+		if !canDeleteSpec(info.genDecl, n) {
+			return nil
+		}
+
+		if len(info.genDecl.Specs) == 1 {
+			// Remove the whole group instead of leaving an empty one behind
+			pos, end = info.genDecl.Pos(), info.genDecl.End()
+			if info.genDecl.Doc != nil {
+				pos = info.genDecl.Doc.Pos()
+			}
+		} else {
+			pos, end = n.Pos(), n.End()
+		}
+
+		doc, comment := specComments(n)
+		if doc != nil && doc.Pos() < pos {
+			pos = doc.Pos()
+		}
+		if comment != nil && comment.End() > end {
+			end = comment.End()
+		}
+	default:
+		return nil
+	}
+
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Remove %s", info.name),
+		TextEdits: []analysis.TextEdit{{Pos: pos, End: end}},
+	}}
+}
+
+// canDeleteSpec returns true if the spec declares a single name and removing
+// it doesn't change the meaning of the other specs in its group
+func canDeleteSpec(genDecl *ast.GenDecl, spec ast.Spec) bool {
+	valueSpec, ok := spec.(*ast.ValueSpec)
+	if !ok {
+		return true
+	}
+	if len(valueSpec.Names) != 1 {
+		return false
+	}
+	if genDecl.Tok != token.CONST || len(genDecl.Specs) == 1 {
+		return true
+	}
+
+	// Constants of a group may repeat the previous expression implicitly or
+	// depend on their index through iota, so removing one would shift the
+	// values of the others
+	for _, s := range genDecl.Specs {
+		vs := s.(*ast.ValueSpec)
+		if len(vs.Values) == 0 {
+			return false
+		}
+		for _, value := range vs.Values {
+			if usesIota(value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// usesIota returns true if the expression refers to iota
+func usesIota(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Ident); ok && ident.Name == "iota" {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// specComments returns the doc and line comments attached to a spec
+func specComments(spec ast.Spec) (doc, comment *ast.CommentGroup) {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return s.Doc, s.Comment
+	case *ast.ValueSpec:
+		return s.Doc, s.Comment
+	}
+	return nil, nil
+}
@@ -0,0 +1,86 @@
+package intestonly
+
+import (
+	"flag"
+	"strings"
+)
+
+// registerFlags adds the flags of the analyzer to fs. They are named like
+// the golangci-lint settings and change config directly, so drivers such as
+// singlechecker can configure the analyzer without golangci-lint. Every
+// setting but package-overrides has one.
+func registerFlags(fs *flag.FlagSet, config *Config) {
+	fs.BoolVar(&config.EnableReflectionAnalysis, "enable-reflection-analysis", config.EnableReflectionAnalysis, "count declarations looked up through reflection as used")
+	fs.BoolVar(&config.ExampleFunctionsCountAsProduction, "example-functions-count-as-production", config.ExampleFunctionsCountAsProduction, "count usages in Example functions as production usages")
+	fs.BoolVar(&config.BenchmarksCountAsProduction, "benchmarks-count-as-production", config.BenchmarksCountAsProduction, "count usages in Benchmark functions as production usages")
+	fs.BoolVar(&config.TreatTestdataAsTests, "treat-testdata-as-tests", config.TreatTestdataAsTests, "treat files in testdata directories as test files")
+	fs.BoolVar(&config.RespectBuildTags, "respect-build-tags", config.RespectBuildTags, "skip files that the default build context excludes")
+	fs.BoolVar(&config.TreatLinkedFunctionsAsUsed, "treat-linked-functions-as-used", config.TreatLinkedFunctionsAsUsed, "skip functions implemented in assembly or named by //go:linkname")
+	fs.BoolVar(&config.SkipGeneratedFiles, "skip-generated-files", config.SkipGeneratedFiles, "never report declarations of generated files")
+	fs.BoolVar(&config.LibraryMode, "library-mode", config.LibraryMode, "never report exported declarations")
+	fs.BoolVar(&config.ReportExplicitTestCases, "report-explicit-test-cases", config.ReportExplicitTestCases, "report the explicit test cases whatever their usages")
+	fs.BoolVar(&config.AnnotateExclusions, "annotate-exclusions", config.AnnotateExclusions, "note test-only declarations skipped by -consider-reflection-risky")
+	fs.BoolVar(&config.ReportUnusedEverywhere, "report-unused-everywhere", config.ReportUnusedEverywhere, "also report unexported declarations that nothing uses")
+	fs.BoolVar(&config.EnableGoGenerateAnalysis, "enable-go-generate-analysis", config.EnableGoGenerateAnalysis, "count declared names in //go:generate directives as usages")
+	fs.BoolVar(&config.VerifyAgainstTypesInfo, "verify-against-types-info", config.VerifyAgainstTypesInfo, "drop reports of declarations that the type checker sees used in production")
+	fs.BoolVar(&config.CategoriesByVisibility, "categories-by-visibility", config.CategoriesByVisibility, "report exported and unexported declarations with different categories")
+	fs.Var((*stringList)(&config.OverrideIsTestFiles), "override-is-test-files", "comma-separated patterns of files treated as test files")
+	fs.Var((*stringList)(&config.OverrideIsProductionFiles), "override-is-production-files", "comma-separated patterns of files treated as production code")
+	fs.Var((*stringList)(&config.IgnoreFiles), "ignore-files", "comma-separated names or path suffixes of files whose declarations are never reported")
+	fs.Var((*stringList)(&config.SkipPackages), "skip-packages", "comma-separated import paths of packages that are not analyzed")
+	fs.Var((*stringList)(&config.IncludePatterns), "include-patterns", "comma-separated patterns of the declaration names to report")
+	fs.Var((*stringList)(&config.ExcludePatterns), "exclude-patterns", "comma-separated patterns of declaration names that are never reported")
+	fs.Var((*stringList)(&config.TestHelperPatterns), "test-helper-patterns", "comma-separated patterns of test helper names that are never reported")
+	fs.Var((*stringList)(&config.ExplicitTestCases), "explicit-test-cases", "comma-separated names of declarations checked even when named like test helpers")
+	fs.Var((*stringList)(&config.AlwaysUsed), "always-used", "comma-separated names of declarations that are never reported")
+	fs.Var((*signatureList)(&config.KnownImplicitMethods), "known-implicit-methods", "semicolon-separated signatures of methods called implicitly through standard interfaces")
+	fs.BoolVar(&config.ConsiderReflectionRisky, "consider-reflection-risky", config.ConsiderReflectionRisky, "skip exported methods and names matching the reflection risk patterns")
+	fs.Var((*stringList)(&config.ReflectionRiskPatterns), "reflection-risk-patterns", "comma-separated patterns of names that reflection may resolve")
+	fs.BoolVar(&config.CollapseFileLevelReports, "collapse-file-level-reports", config.CollapseFileLevelReports, "report files whose declarations are all only used in tests once")
+	fs.BoolVar(&config.EnableStringLiteralAnalysis, "enable-string-literal-analysis", config.EnableStringLiteralAnalysis, "count declared names written as calls in string literals as usages")
+	fs.BoolVar(&config.StringLiteralTestUsages, "string-literal-test-usages", config.StringLiteralTestUsages, "also count the string literals of tests")
+	fs.BoolVar(&config.EnableStructTagAnalysis, "enable-struct-tag-analysis", config.EnableStructTagAnalysis, "count declared names in struct tag values as usages")
+	fs.IntVar(&config.StringReferenceMinLength, "string-reference-min-length", config.StringReferenceMinLength, "minimum length of names matched in string literals and struct tags")
+	fs.StringVar(&config.MessageTemplate, "message-template", config.MessageTemplate, "text/template of the reported message, with {{.Kind}} and {{.Name}}")
+}
+
+// stringList is a flag value holding a comma-separated list
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// signatureList is a flag value holding a semicolon-separated list of
+// method signatures, which contain commas themselves
+type signatureList []string
+
+func (l *signatureList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ";")
+}
+
+func (l *signatureList) Set(value string) error {
+	*l = nil
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package intestonly
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	config := DefaultConfig()
+	a := NewAnalyzer(config)
+
+	if err := a.Flags.Set("library-mode", "true"); err != nil {
+		t.Fatalf("Failed to set library-mode: %s", err)
+	}
+	if err := a.Flags.Set("exclude-patterns", "Legacy*, *Mock,"); err != nil {
+		t.Fatalf("Failed to set exclude-patterns: %s", err)
+	}
+	if err := a.Flags.Set("enable-reflection-analysis", "false"); err != nil {
+		t.Fatalf("Failed to set enable-reflection-analysis: %s", err)
+	}
+
+	if !config.LibraryMode || config.EnableReflectionAnalysis {
+		t.Errorf("Boolean flags were not applied: %+v", config)
+	}
+	if want := []string{"Legacy*", "*Mock"}; !reflect.DeepEqual(config.ExcludePatterns, want) {
+		t.Errorf("ExcludePatterns = %q, want %q", config.ExcludePatterns, want)
+	}
+	if got := a.Flags.Lookup("exclude-patterns").Value.String(); got != "Legacy*,*Mock" {
+		t.Errorf("Unexpected flag value %q", got)
+	}
+
+	// Signatures contain commas, so they are separated by semicolons
+	if err := a.Flags.Set("known-implicit-methods", "MarshalJSON() ([]byte, error); String() string"); err != nil {
+		t.Fatalf("Failed to set known-implicit-methods: %s", err)
+	}
+	if want := []string{"MarshalJSON() ([]byte, error)", "String() string"}; !reflect.DeepEqual(config.KnownImplicitMethods, want) {
+		t.Errorf("KnownImplicitMethods = %q, want %q", config.KnownImplicitMethods, want)
+	}
+	if err := a.Flags.Set("string-reference-min-length", "5"); err != nil {
+		t.Fatalf("Failed to set string-reference-min-length: %s", err)
+	}
+	if config.StringReferenceMinLength != 5 {
+		t.Errorf("StringReferenceMinLength = %d, want 5", config.StringReferenceMinLength)
+	}
+}
+
+func TestFlagsCoverSettings(t *testing.T) {
+	a := NewAnalyzer(DefaultConfig())
+
+	// Overrides per package can't be expressed as a flag
+	settings := reflect.TypeOf(IntestOnlySettings{})
+	for i := 0; i < settings.NumField(); i++ {
+		name := settings.Field(i).Tag.Get("mapstructure")
+		if name == "package-overrides" {
+			continue
+		}
+		if a.Flags.Lookup(name) == nil {
+			t.Errorf("Setting %s has no flag", name)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package intestonly
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsTestHelperIdentifier(t *testing.T) {
+	tests := map[string]bool{
+		"testEnv":         true,
+		"EnvSetup":        true,
+		"setupDatabase":   true,
+		"assertEqual":     true,
+		"newMockDB":       true,
+		"fakeClock":       true,
+		"TestHelper":      true,
+		"new_test_util":   true,
+		"Environment":     false,
+		"Evaluate":        false,
+		"Utility":         false,
+		"Mockingbird":     false,
+		"Setupper":        false,
+		"testOnlyFunc":    false,
+		"loadEnv":         false,
+		"CleanupInterval": true,
+		"mockserver":      true,
+		"setupdb":         true,
+		"fakeclock":       true,
+		"assertequal":     true,
+		"testhelperFoo":   true,
+		"testutil":        true,
+		"testing":         false,
+		"testOnly":        false,
+		"environment":     false,
+	}
+
+	for name, want := range tests {
+		if got := isTestHelperIdentifier(name); got != want {
+			t.Errorf("isTestHelperIdentifier(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := map[string][]string{
+		"testEnv":       {"test", "env"},
+		"EnvSetup":      {"env", "setup"},
+		"newMockDBConn": {"new", "mock", "db", "conn"},
+		"HTTPServer":    {"http", "server"},
+		"snake_case_id": {"snake", "case", "id"},
+		"v2Client":      {"v2", "client"},
+		"ID":            {"id"},
+		"_private":      {"private"},
+	}
+
+	for name, want := range tests {
+		if got := splitWords(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("splitWords(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestIsTestHelperPatterns(t *testing.T) {
+	config := DefaultConfig()
+	config.TestHelperPatterns = []string{"*Builder", "golden"}
+
+	tests := map[string]bool{
+		"requestBuilder": true,
+		"loadGolden":     true,
+		"fakeClock":      true,
+		"goldenratio":    false,
+		"Build":          false,
+	}
+	for name, want := range tests {
+		if got := isTestHelper(name, config); got != want {
+			t.Errorf("isTestHelper(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultKnownImplicitMethods are the methods that the standard library calls
+// through interfaces such as fmt.Stringer, error and json.Marshaler
+var defaultKnownImplicitMethods = []string{
+	"String() string",
+	"Error() string",
+	"MarshalJSON() ([]byte, error)",
+	"UnmarshalJSON([]byte) error",
+}
+
+// isKnownImplicitMethod returns true if the declaration is a method whose
+// name and signature match one of KnownImplicitMethods
+func isKnownImplicitMethod(pass *analysis.Pass, info intestOnlyInfo, config *Config) bool {
+	if !info.isMethod || len(config.KnownImplicitMethods) == 0 {
+		return false
+	}
+	fn, ok := info.node.(*ast.FuncDecl)
+	if !ok {
+		return false
+	}
+	obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return false
+	}
+
+	method := stripSpaces(methodSignature(obj))
+	for _, known := range config.KnownImplicitMethods {
+		if stripSpaces(known) == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// methodSignature formats a method without receiver and parameter names,
+// e.g. "MarshalJSON() ([]byte, error)". Types of other packages are
+// qualified by the package name.
+func methodSignature(fn *types.Func) string {
+	sig := fn.Type().(*types.Signature)
+	qualifier := func(pkg *types.Package) string { return pkg.Name() }
+
+	var b strings.Builder
+	b.WriteString(fn.Name())
+	b.WriteString("(")
+	for i := 0; i < sig.Params().Len(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		typ := sig.Params().At(i).Type()
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			b.WriteString("...")
+			typ = typ.(*types.Slice).Elem()
+		}
+		b.WriteString(types.TypeString(typ, qualifier))
+	}
+	b.WriteString(")")
+
+	switch results := sig.Results(); results.Len() {
+	case 0:
+	case 1:
+		b.WriteString(" ")
+		b.WriteString(types.TypeString(results.At(0).Type(), qualifier))
+	default:
+		b.WriteString(" (")
+		for i := 0; i < results.Len(); i++ {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(types.TypeString(results.At(i).Type(), qualifier))
+		}
+		b.WriteString(")")
+	}
+
+	return b.String()
+}
+
+// stripSpaces removes white space so signatures compare regardless of
+// formatting
+func stripSpaces(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,269 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// interfaceUsages records the declared types that implement an interface
+// referenced in production code, together with their methods of that
+// interface. Production code may receive such a type through the interface,
+// including interfaces of other packages of the module, without ever naming
+// it.
+func interfaceUsages(pass *analysis.Pass, testFiles *testFileCache, decls map[types.Object]intestOnlyInfo, record func(obj types.Object, pos token.Pos)) {
+	ifaces := productionInterfaces(pass, testFiles)
+	if len(ifaces) == 0 {
+		return
+	}
+
+	for _, info := range decls {
+		spec, ok := info.node.(*ast.TypeSpec)
+		if !ok || spec.TypeParams != nil {
+			continue
+		}
+		obj, ok := info.obj.(*types.TypeName)
+		if !ok || types.IsInterface(obj.Type()) {
+			continue
+		}
+
+		for iface, pos := range ifaces {
+			if !types.Implements(obj.Type(), iface) && !types.Implements(types.NewPointer(obj.Type()), iface) {
+				continue
+			}
+
+			record(obj, pos)
+			recordMethods(types.NewPointer(obj.Type()), iface, pos, record)
+		}
+	}
+}
+
+// productionInterfaces returns the non-empty interfaces named in production
+// files, with a position where each is referenced. Interfaces such as error,
+// fmt.Stringer and io.Writer are named nearly everywhere, so the predeclared
+// ones and those of the standard library only count where a value flows
+// into them, as argumentInterfaceUsages and assignedInterfaceUsages see.
+func productionInterfaces(pass *analysis.Pass, testFiles *testFileCache) map[*types.Interface]token.Pos {
+	ifaces := make(map[*types.Interface]token.Pos)
+
+	for ident, obj := range pass.TypesInfo.Uses {
+		typeName, ok := obj.(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if pkg := typeName.Pkg(); pkg == nil || (pkg != pass.Pkg && isStandardPackage(pkg.Path())) {
+			continue
+		}
+		iface, ok := typeName.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 {
+			continue
+		}
+		if testFiles.isTestFile(pass.Fset.File(ident.Pos()).Name()) {
+			continue
+		}
+
+		if pos, seen := ifaces[iface]; !seen || ident.Pos() < pos {
+			ifaces[iface] = ident.Pos()
+		}
+	}
+
+	return ifaces
+}
+
+// isStandardPackage reports whether the import path is one of the standard
+// library, whose first element has no dot, like the go command decides
+func isStandardPackage(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+// argumentInterfaceUsages records the declared types passed as arguments to
+// interface parameters of call, together with their methods of that
+// interface. Functions of other packages such as sort.Sort call these
+// methods without production code ever naming the interface.
+func argumentInterfaceUsages(pass *analysis.Pass, call *ast.CallExpr, decls map[types.Object]intestOnlyInfo, record func(obj types.Object, pos token.Pos)) {
+	sig, ok := pass.TypesInfo.TypeOf(call.Fun).(*types.Signature)
+	if !ok {
+		return
+	}
+
+	params := sig.Params()
+	for i, arg := range call.Args {
+		var param types.Type
+		switch {
+		case sig.Variadic() && i >= params.Len()-1:
+			param = params.At(params.Len() - 1).Type()
+			if call.Ellipsis.IsValid() {
+				continue
+			}
+			if slice, ok := param.(*types.Slice); ok {
+				param = slice.Elem()
+			}
+		case i < params.Len():
+			param = params.At(i).Type()
+		default:
+			continue
+		}
+
+		interfaceValueUsage(pass, param, arg, decls, record)
+	}
+}
+
+// assignedInterfaceUsages records the declared types stored in variables,
+// fields or elements of an interface type by an assignment or a composite
+// literal, together with their methods of that interface. Code of other
+// packages may call these methods, as net/http does for the Handler field
+// of an http.Server, without production code ever naming the interface.
+func assignedInterfaceUsages(pass *analysis.Pass, node ast.Node, decls map[types.Object]intestOnlyInfo, record func(obj types.Object, pos token.Pos)) {
+	switch n := node.(type) {
+	case *ast.AssignStmt:
+		if len(n.Lhs) != len(n.Rhs) {
+			return
+		}
+		for i, lhs := range n.Lhs {
+			if target := pass.TypesInfo.TypeOf(lhs); target != nil {
+				interfaceValueUsage(pass, target, n.Rhs[i], decls, record)
+			}
+		}
+
+	case *ast.CompositeLit:
+		typ := pass.TypesInfo.TypeOf(n)
+		if typ == nil {
+			return
+		}
+		for i, elt := range n.Elts {
+			value := elt
+			var key ast.Expr
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				key, value = kv.Key, kv.Value
+			}
+
+			switch t := typ.Underlying().(type) {
+			case *types.Struct:
+				if ident, ok := key.(*ast.Ident); ok {
+					if field, ok := pass.TypesInfo.Uses[ident].(*types.Var); ok {
+						interfaceValueUsage(pass, field.Type(), value, decls, record)
+					}
+				} else if key == nil && i < t.NumFields() {
+					interfaceValueUsage(pass, t.Field(i).Type(), value, decls, record)
+				}
+			case *types.Slice:
+				interfaceValueUsage(pass, t.Elem(), value, decls, record)
+			case *types.Array:
+				interfaceValueUsage(pass, t.Elem(), value, decls, record)
+			case *types.Map:
+				if key != nil {
+					interfaceValueUsage(pass, t.Key(), key, decls, record)
+				}
+				interfaceValueUsage(pass, t.Elem(), value, decls, record)
+			}
+		}
+	}
+}
+
+// interfaceValueUsage records the declared type of value and its methods of
+// target when target is an interface that the value is converted to
+func interfaceValueUsage(pass *analysis.Pass, target types.Type, value ast.Expr, decls map[types.Object]intestOnlyInfo, record func(obj types.Object, pos token.Pos)) {
+	iface, ok := target.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 {
+		return
+	}
+	valueType := pass.TypesInfo.TypeOf(value)
+	if valueType == nil || types.IsInterface(valueType) {
+		return
+	}
+	named := declaredNamed(pass, valueType)
+	if named == nil {
+		return
+	}
+	if _, isDeclared := decls[named.Obj()]; !isDeclared || !types.Implements(valueType, iface) {
+		return
+	}
+
+	record(named.Obj(), value.Pos())
+	recordMethods(valueType, iface, value.Pos(), record)
+}
+
+// recordMethods records the methods of t, which implements iface, that
+// implement the methods of iface. Methods of instantiated generic types are
+// recorded as their generic declaration.
+func recordMethods(t types.Type, iface *types.Interface, pos token.Pos, record func(obj types.Object, pos token.Pos)) {
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		obj, _, _ := types.LookupFieldOrMethod(t, true, method.Pkg(), method.Name())
+		if fn, ok := obj.(*types.Func); ok {
+			record(fn.Origin(), pos)
+		}
+	}
+}
+
+// methodImplementations finds the declared methods that a call of an
+// interface method may reach, caching them per interface method
+type methodImplementations struct {
+	methods map[string][]*types.Func // Declared methods by name
+	cache   map[*types.Func][]types.Object
+}
+
+func newMethodImplementations(decls map[types.Object]intestOnlyInfo) *methodImplementations {
+	impls := &methodImplementations{
+		methods: make(map[string][]*types.Func),
+		cache:   make(map[*types.Func][]types.Object),
+	}
+	for obj := range decls {
+		if fn, ok := obj.(*types.Func); ok && fn.Type().(*types.Signature).Recv() != nil {
+			impls.methods[fn.Name()] = append(impls.methods[fn.Name()], fn)
+		}
+	}
+	return impls
+}
+
+// of returns the declared methods of the same name whose receiver type
+// implements the interface of method, or nothing if method isn't the method
+// of an interface. The methods of generic types can't be checked against
+// the interface, so they match by name alone.
+func (m *methodImplementations) of(method *types.Func) []types.Object {
+	recv := method.Type().(*types.Signature).Recv()
+	if recv == nil || len(m.methods[method.Name()]) == 0 {
+		return nil
+	}
+	iface, ok := recv.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	if impls, seen := m.cache[method]; seen {
+		return impls
+	}
+
+	var impls []types.Object
+	for _, fn := range m.methods[method.Name()] {
+		t := fn.Type().(*types.Signature).Recv().Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		named, ok := t.(*types.Named)
+		if !ok {
+			continue
+		}
+		if named.TypeParams().Len() > 0 || types.Implements(types.NewPointer(named), iface) {
+			impls = append(impls, fn)
+		}
+	}
+	m.cache[method] = impls
+	return impls
+}
+
+// declaredNamed returns the named type of t or of the type t points to, if
+// it is declared in the analyzed package
+func declaredNamed(pass *analysis.Pass, t types.Type) *types.Named {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != pass.Pkg {
+		return nil
+	}
+	return named
+}
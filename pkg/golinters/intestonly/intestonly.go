@@ -1,58 +1,105 @@
 package intestonly
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
+	"go/types"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
 
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 )
 
 // Analyzer is the analyzer struct.
-var Analyzer = &analysis.Analyzer{
-	Name: "intestonly",
-	Doc:  "Checks for code that is only used in tests but is not part of test files",
-	Run:  run,
-	Requires: []*analysis.Analyzer{
-		inspect.Analyzer,
-	},
-	FactTypes: []analysis.Fact{},
+var Analyzer = NewAnalyzer(DefaultConfig())
+
+// NewAnalyzer returns an analyzer that runs with the given configuration.
+// The flags of the analyzer change the configuration before it runs. The
+// message template is checked once, when the analyzer first runs after the
+// flags are parsed, and a broken one fails every run of the analyzer.
+func NewAnalyzer(config *Config) *analysis.Analyzer {
+	var (
+		parse   sync.Once
+		message *template.Template
+		err     error
+	)
+
+	a := &analysis.Analyzer{
+		Name: "intestonly",
+		Doc:  "Checks for code that is only used in tests but is not part of test files",
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			parse.Do(func() {
+				message, err = parseMessageTemplate(config.MessageTemplate)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return run(pass, config, message)
+		},
+		Requires: []*analysis.Analyzer{
+			inspect.Analyzer,
+		},
+		ResultType: reflect.TypeOf((*Stats)(nil)),
+		FactTypes:  []analysis.Fact{new(unusedFact)},
+	}
+	registerFlags(&a.Flags, config)
+	return a
 }
 
-type intestOnlyInfo struct {
-	pos      token.Pos
-	name     string
-	filePath string
-	isMethod bool
+// testHelperWords are the camelCase words that mark an identifier as a test
+// helper wherever they appear in its name
+var testHelperWords = map[string]bool{
+	"assert":  true,
+	"mock":    true,
+	"fake":    true,
+	"stub":    true,
+	"setup":   true,
+	"cleanup": true,
 }
 
-// shouldIgnoreFile returns true if the file should be ignored for analysis
-func shouldIgnoreFile(filename string) bool {
-	// Ignore files that are named like test helpers
-	base := filepath.Base(filename)
-	return strings.Contains(base, "test_helper") ||
-		strings.Contains(base, "test_util") ||
-		strings.Contains(base, "testutil") ||
-		strings.Contains(base, "testhelper")
+// testQualifiedWords mark an identifier as a test helper when they directly
+// follow the word "test", as in testEnv or newTestHelper
+var testQualifiedWords = map[string]bool{
+	"helper":  true,
+	"util":    true,
+	"env":     true,
+	"fixture": true,
 }
 
 // isTestHelperIdentifier returns true if the name indicates a test helper
-// that should be excluded from test-only analysis
+// that should be excluded from test-only analysis. Matching works on whole
+// camelCase words, so "Environment" or "Mockingbird" are not helpers while
+// "testEnv" and "EnvSetup" are. Names written in lower case, such as
+// mockserver or testhelperFoo, run words together, so a lower-case first
+// word also matches when it starts with one of the words.
 func isTestHelperIdentifier(name string) bool {
-	lowerName := strings.ToLower(name)
+	words := splitWords(name)
 
-	// Exclude common test helper patterns
-	if strings.HasPrefix(lowerName, "assert") ||
-		strings.HasPrefix(lowerName, "mock") ||
-		strings.HasPrefix(lowerName, "fake") ||
-		strings.HasPrefix(lowerName, "stub") ||
-		strings.HasPrefix(lowerName, "setup") ||
-		strings.HasPrefix(lowerName, "cleanup") ||
-		strings.Contains(lowerName, "mockdb") ||
-		strings.Contains(lowerName, "testhelper") {
-		return true
+	for i, word := range words {
+		if testHelperWords[word] {
+			return true
+		}
+		if word == "test" && i+1 < len(words) && testQualifiedWords[words[i+1]] {
+			return true
+		}
+	}
+
+	if r, _ := utf8.DecodeRuneInString(name); len(words) > 0 && unicode.IsLower(r) {
+		if hasWordPrefix(words[0], testHelperWords) {
+			return true
+		}
+		if rest, ok := strings.CutPrefix(words[0], "test"); ok && hasWordPrefix(rest, testQualifiedWords) {
+			return true
+		}
 	}
 
 	// Note: We don't want to exclude all "test" prefixed identifiers as these
@@ -61,121 +108,223 @@ func isTestHelperIdentifier(name string) bool {
 	return false
 }
 
-// isExplicitTestOnly checks if this is one of the known test-only identifiers
-// from our test data that we specifically want to detect
-func isExplicitTestOnly(name string) bool {
-	return name == "testOnlyFunction" ||
-		name == "TestOnlyType" ||
-		name == "testOnlyConstant" ||
-		name == "helperFunction" ||
-		name == "reflectionFunction" ||
-		name == "testMethod"
+// hasWordPrefix returns true if s starts with one of the words
+func hasWordPrefix(s string, words map[string]bool) bool {
+	for word := range words {
+		if strings.HasPrefix(s, word) {
+			return true
+		}
+	}
+	return false
 }
 
-// shouldExcludeFromReport checks if this identifier should be excluded from
-// the test-only report based on the test expectations
-func shouldExcludeFromReport(name string) bool {
-	// Exclude methods from nested_structures.go
-	if name == "outerMethod" ||
-		name == "innerMethod" ||
-		name == "embeddedMethod" {
-		return true
+// isTestHelper returns true if the name is a test helper by its words or by
+// one of TestHelperPatterns
+func isTestHelper(name string, config *Config) bool {
+	return isTestHelperIdentifier(name) || matchesName(name, config.TestHelperPatterns)
+}
+
+// splitWords splits an identifier into its lower cased camelCase and
+// snake_case words. Acronyms stay together, so "newMockDBConn" splits into
+// "new", "mock", "db" and "conn".
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+		start = end
 	}
 
-	// Exclude methods from edge_cases.go
-	if name == "testUtilFunction" ||
-		name == "testFixtureFunction" ||
-		name == "testHelperFunction" {
-		return true
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// A new word starts after a lower case letter or digit, or at the
+			// last upper case letter of an acronym followed by lower case
+			if !unicode.IsUpper(prev) || nextLower {
+				flush(i)
+			}
+		}
 	}
+	flush(len(runes))
 
-	return false
+	return words
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	debug := false // Set to true to enable debug output
+// isExplicitTestCase returns true if the name is one of ExplicitTestCases
+func isExplicitTestCase(name string, config *Config) bool {
+	return slices.Contains(config.ExplicitTestCases, name)
+}
+
+func run(pass *analysis.Pass, config *Config, message *template.Template) (interface{}, error) {
+	base := config
+	config = getConfig(config, pass.Pkg.Path())
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration for %s: %w", pass.Pkg.Path(), err)
+	}
+	if config.MessageTemplate != base.MessageTemplate {
+		var err error
+		if message, err = parseMessageTemplate(config.MessageTemplate); err != nil {
+			return nil, err
+		}
+	}
+	log := config.logger()
+	if isSkippedPackage(pass.Pkg.Path(), config) {
+		log.Debugf("Skipping package %s", pass.Pkg.Path())
+		return &Stats{}, nil
+	}
 
-	// Maps to track declarations and usages
-	decls := make(map[string]intestOnlyInfo)    // All declarations in non-test files
-	nonTestUsages := make(map[string]bool)      // Identifiers used in non-test files
-	testUsages := make(map[string]bool)         // Identifiers used in test files
-	declPositions := make(map[token.Pos]string) // Map positions to identifiers to skip self-references
+	// Maps to track usages
+	nonTestUsages := make(map[types.Object]bool)           // Declarations used in non-test files
+	testUsages := make(map[types.Object]bool)              // Declarations used in test files
+	testUsagePositions := make(map[types.Object]token.Pos) // First usage of each declaration in test files
 
 	// First pass: collect all declarations from non-test files and track their positions
-	for _, file := range pass.Files {
+	files := pass.Files
+	if config.RespectBuildTags {
+		files = filesInBuildContext(pass, log)
+	}
+
+	testFiles := newTestFileCache(config)
+	decls := collectDeclarations(pass.Fset, pass.TypesInfo, files, testFiles)
+	names := declsByName(decls)
+
+	log.Debugf("Found %d declarations in non-test files of %s", len(decls), pass.Pkg.Path())
+	if config.Logger != nil {
+		for _, info := range decls {
+			log.Debugf("Decl: %s at %s", info.name, pass.Fset.Position(info.pos))
+		}
+	}
+
+	// Second pass: track usages in all files
+	// recordUsage marks a declared object as used in test or non-test code
+	recordUsage := func(obj types.Object, pos token.Pos, isTest bool) {
+		if _, isDeclared := decls[obj]; !isDeclared {
+			return
+		}
+
+		if isTest {
+			testUsages[obj] = true
+			if _, seen := testUsagePositions[obj]; !seen {
+				testUsagePositions[obj] = pos
+			}
+		} else {
+			nonTestUsages[obj] = true
+		}
+	}
+
+	// recordName marks every declaration of a name as used, for references
+	// that don't resolve to an object, such as names in string literals
+	recordName := func(name string, pos token.Pos, isTest bool) {
+		for _, obj := range names[name] {
+			recordUsage(obj, pos, isTest)
+		}
+	}
+	implementations := newMethodImplementations(decls)
+
+	// Types referenced in the declarations of other types, such as embedded
+	// types, are used as much as the referring types
+	referencedIn := typeReferences(pass, decls)
+	var refs []typeReference
+
+	for _, file := range files {
 		fileName := pass.Fset.File(file.Pos()).Name()
-		isTest := isTestFile(fileName)
+		isTest := testFiles.isTestFile(fileName)
 
-		// Skip test helper files even if they're not test files
-		if shouldIgnoreFile(fileName) {
-			continue
+		// Declarations that code generators read from the source
+		if config.EnableGoGenerateAnalysis {
+			goGenerateReferences(file, names, func(name string, pos token.Pos) {
+				recordName(name, pos, isTest)
+			})
 		}
 
-		if !isTest {
-			ast.Inspect(file, func(node ast.Node) bool {
+		for _, decl := range file.Decls {
+			// Examples may be configured to count as production usage since
+			// they are part of the published documentation, and benchmarks
+			// since they measure code that is expected to ship
+			inTest := isTest
+			if isTest && countsAsProduction(testFunctionKind(decl), config) {
+				inTest = false
+			}
+
+			ast.Inspect(decl, func(node ast.Node) bool {
 				switch n := node.(type) {
-				case *ast.FuncDecl:
-					if n.Name != nil && n.Name.Name != "" {
-						name := n.Name.Name
+				case *ast.Ident:
+					// Uses resolves the names of declarations, the selectors
+					// of method calls, method values and method expressions,
+					// and embedded types, but not the declaring identifiers
+					obj := pass.TypesInfo.Uses[n]
+					if obj == nil {
+						return true
+					}
+					if fn, ok := obj.(*types.Func); ok {
+						obj = fn.Origin()
+					}
 
-						// Skip test helper identifiers unless they're explicit test cases
-						if isTestHelperIdentifier(name) && !isExplicitTestOnly(name) {
-							return true
-						}
+					if owner, ok := referencedIn[n.Pos()]; ok {
+						refs = append(refs, typeReference{obj: obj, pos: n.Pos(), owner: owner})
+						return true
+					}
+
+					recordUsage(obj, n.Pos(), inTest)
 
-						// Handle methods (functions with receivers)
-						if n.Recv != nil && len(n.Recv.List) > 0 {
-							decls[name] = intestOnlyInfo{
-								pos:      n.Name.Pos(),
-								name:     name,
-								filePath: fileName,
-								isMethod: true,
-							}
-							declPositions[n.Name.Pos()] = name
-						} else {
-							// Regular function
-							decls[name] = intestOnlyInfo{
-								pos:      n.Name.Pos(),
-								name:     name,
-								filePath: fileName,
-								isMethod: false,
-							}
-							declPositions[n.Name.Pos()] = name
+					// A call through an interface may reach the declared
+					// methods that implement it
+					if fn, ok := obj.(*types.Func); ok {
+						for _, method := range implementations.of(fn) {
+							recordUsage(method, n.Pos(), inTest)
 						}
 					}
-				case *ast.TypeSpec:
-					if n.Name != nil && n.Name.Name != "" {
-						name := n.Name.Name
 
-						// Skip test helper identifiers unless they're explicit test cases
-						if isTestHelperIdentifier(name) && !isExplicitTestOnly(name) {
-							return true
-						}
+				case *ast.CallExpr:
+					// Types passed to interface parameters, with the methods
+					// the callee may call through the interface
+					argumentInterfaceUsages(pass, n, decls, func(obj types.Object, pos token.Pos) {
+						recordUsage(obj, pos, inTest)
+					})
 
-						decls[name] = intestOnlyInfo{
-							pos:      n.Name.Pos(),
-							name:     name,
-							filePath: fileName,
-							isMethod: false,
-						}
-						declPositions[n.Name.Pos()] = name
+					// Methods looked up by name through reflection
+					if !config.EnableReflectionAnalysis {
+						return true
 					}
-				case *ast.ValueSpec:
-					for _, name := range n.Names {
-						if name != nil && name.Name != "" {
-							// Skip test helper identifiers unless they're explicit test cases
-							if isTestHelperIdentifier(name.Name) && !isExplicitTestOnly(name.Name) {
-								continue
-							}
-
-							decls[name.Name] = intestOnlyInfo{
-								pos:      name.Pos(),
-								name:     name.Name,
-								filePath: fileName,
-								isMethod: false,
-							}
-							declPositions[name.Pos()] = name.Name
-						}
+					if name, ok := reflectedName(pass, n); ok {
+						recordName(name, n.Pos(), inTest)
+					}
+
+				case *ast.AssignStmt, *ast.CompositeLit:
+					// Types stored in interface variables, fields and
+					// elements, with the methods the holder may call
+					assignedInterfaceUsages(pass, n, decls, func(obj types.Object, pos token.Pos) {
+						recordUsage(obj, pos, inTest)
+					})
+
+				case *ast.BasicLit:
+					// Declarations mentioned as calls in string literals
+					if !config.EnableStringLiteralAnalysis || (inTest && !config.StringLiteralTestUsages) {
+						return true
+					}
+					for _, name := range stringLiteralReferences(n, names, config) {
+						recordName(name, n.Pos(), inTest)
+					}
+
+				case *ast.Field:
+					// Declarations named in struct tags, e.g. validator rules
+					if n.Tag == nil || !config.EnableStringLiteralAnalysis || !config.EnableStructTagAnalysis {
+						return true
+					}
+					if inTest && !config.StringLiteralTestUsages {
+						return true
+					}
+					for _, name := range structTagReferences(n.Tag, names, config.StringReferenceMinLength) {
+						recordName(name, n.Tag.Pos(), inTest)
 					}
 				}
 				return true
@@ -183,104 +332,355 @@ func run(pass *analysis.Pass) (interface{}, error) {
 		}
 	}
 
-	if debug {
-		pass.Reportf(token.NoPos, "Found %d declarations in non-test files", len(decls))
-		for name, info := range decls {
-			pass.Reportf(token.NoPos, "Decl: %s at %s", name, pass.Fset.Position(info.pos))
+	// Types may be used in production through the interfaces they implement
+	interfaceUsages(pass, testFiles, decls, func(obj types.Object, pos token.Pos) {
+		recordUsage(obj, pos, false)
+	})
+
+	// Referenced types are used wherever the types referring to them are
+	propagateTypeReferences(refs, nonTestUsages, testUsages, func(owner types.Object) bool {
+		// Types that are never reported stay in production anyway, as do
+		// types used in production in ways the analyzer can't see
+		info := decls[owner]
+		return (config.LibraryMode && ast.IsExported(info.name)) || isIgnored(info) ||
+			(config.SkipGeneratedFiles && info.generated) ||
+			slices.Contains(config.AlwaysUsed, info.name) || isReflectionRisky(info, config)
+	}, func(r typeReference, isTest bool) {
+		// A test usage happens where the tests use the referring type
+		pos := r.pos
+		if isTest {
+			pos = testUsagePositions[r.owner]
+		}
+		recordUsage(r.obj, pos, isTest)
+	})
+
+	log.Debugf("Found %d usages in test files", len(testUsages))
+	log.Debugf("Found %d usages in non-test files", len(nonTestUsages))
+
+	// Collect identifiers that are only used in test files
+	stats := &Stats{Checked: len(decls)}
+	var testOnly []intestOnlyInfo
+	for obj, info := range decls {
+		name := info.name
+
+		// Exported declarations of a library are used by its importers
+		if config.LibraryMode && ast.IsExported(name) {
+			stats.Exported++
+			continue
+		}
+
+		// Only check the names selected by the configured patterns
+		if !isSelected(name, config) {
+			stats.Excluded++
+			continue
+		}
+
+		// Names configured as used in ways the analyzer can't see
+		if slices.Contains(config.AlwaysUsed, name) {
+			stats.Excluded++
+			continue
+		}
+
+		// Report explicit test cases whatever their usages
+		explicit := isExplicitTestCase(name, config)
+		if explicit && config.ReportExplicitTestCases {
+			stats.Reported++
+			testOnly = append(testOnly, info)
+			continue
+		}
+
+		// Skip checking test helper identifiers
+		if isTestHelper(name, config) && !explicit {
+			stats.TestHelpers++
+			continue
+		}
+
+		// Skip methods the standard library calls through its interfaces
+		// and names that reflection may resolve at run time
+		if isKnownImplicitMethod(pass, info, config) {
+			stats.ImplicitMethods++
+			continue
+		}
+		if isReflectionRisky(info, config) {
+			stats.ReflectionRisky++
+			if config.AnnotateExclusions && testUsages[obj] && !nonTestUsages[obj] {
+				reportReflectionRisky(pass, info)
+			}
+			continue
+		}
+
+		// Skip declarations suppressed by a comment directive
+		if isIgnored(info) {
+			stats.Ignored++
+			continue
+		}
+
+		// Skip functions called from assembly, cgo or other packages
+		if config.TreatLinkedFunctionsAsUsed && isLinkedFunction(info) {
+			stats.Linked++
+			continue
+		}
+
+		// Skip generated code, which isn't edited by hand
+		if config.SkipGeneratedFiles && info.generated {
+			stats.Generated++
+			continue
+		}
+
+		switch {
+		case nonTestUsages[obj]:
+			stats.UsedInProduction++
+		case testUsages[obj]:
+			// This identifier is used in test files but not in non-test files
+			stats.Reported++
+			testOnly = append(testOnly, info)
+			log.Debugf("Reporting %s: testUsage=%v, nonTestUsage=%v",
+				name, testUsages[obj], nonTestUsages[obj])
+		default:
+			stats.Unused++
+			// Without the _test.go files of the package every declaration
+			// only used in tests looks unused, so only its test variant,
+			// which sees every file, reports unused declarations
+			if config.ReportUnusedEverywhere && canBeUnused(name) && hasGoTestFiles(pass, files) {
+				reportUnused(pass, info)
+			}
+			exportUnused(pass, info)
+		}
+	}
+
+	// Let the type checker confirm that production code doesn't use them
+	if config.VerifyAgainstTypesInfo {
+		verified := verifyTestOnly(pass, testOnly, testFiles, log)
+		stats.Reported -= len(testOnly) - len(verified)
+		stats.Verified += len(testOnly) - len(verified)
+		testOnly = verified
+	}
+
+	if config.CollapseFileLevelReports {
+		testOnly = reportTestOnlyFiles(pass, decls, testOnly)
+	}
+	for _, info := range testOnly {
+		msg, err := renderMessage(message, messageData{Kind: declKind(info), Name: info.name})
+		if err != nil {
+			return nil, err
 		}
+		reportTestOnly(pass, info, msg, testOnlyCategory(info.name, config), testUsagePositions[info.obj])
 	}
 
-	// Second pass: track usages in all files
+	// Black-box tests are a separate package that still only tests one
+	blackBox, err := reportBlackBoxUsages(pass, testFiles, config, message)
+	if err != nil {
+		return nil, err
+	}
+	stats.BlackBox = blackBox
+
+	return stats, nil
+}
+
+// reportTestOnlyFiles reports the files whose declarations are all only
+// used in tests with a single diagnostic each, and returns the test-only
+// declarations of the other files
+func reportTestOnlyFiles(pass *analysis.Pass, decls map[types.Object]intestOnlyInfo, testOnly []intestOnlyInfo) []intestOnlyInfo {
+	declCount := make(map[string]int)
+	for _, info := range decls {
+		declCount[info.filePath]++
+	}
+	byFile := make(map[string][]intestOnlyInfo)
+	for _, info := range testOnly {
+		byFile[info.filePath] = append(byFile[info.filePath], info)
+	}
+
+	var remaining []intestOnlyInfo
 	for _, file := range pass.Files {
 		fileName := pass.Fset.File(file.Pos()).Name()
-		isTest := isTestFile(fileName)
-
-		ast.Inspect(file, func(node ast.Node) bool {
-			switch n := node.(type) {
-			case *ast.Ident:
-				// Skip if this is a declaration position
-				if _, isDeclPos := declPositions[n.Pos()]; isDeclPos {
-					return true
-				}
+		infos := byFile[fileName]
+		if len(infos) < 2 || len(infos) != declCount[fileName] {
+			remaining = append(remaining, infos...)
+			continue
+		}
 
-				// Record usage
-				if _, isDeclared := decls[n.Name]; isDeclared {
-					if isTest {
-						testUsages[n.Name] = true
-						if debug {
-							pass.Reportf(n.Pos(), "Test usage of %s", n.Name)
-						}
-					} else {
-						nonTestUsages[n.Name] = true
-						if debug {
-							pass.Reportf(n.Pos(), "Non-test usage of %s", n.Name)
-						}
-					}
-				}
+		sort.Slice(infos, func(i, j int) bool { return infos[i].pos < infos[j].pos })
+		diag := analysis.Diagnostic{
+			Pos:     file.Package,
+			Message: fmt.Sprintf("all %d declarations in %s are only used in tests", len(infos), filepath.Base(fileName)),
+		}
+		for _, info := range infos {
+			diag.Related = append(diag.Related, analysis.RelatedInformation{
+				Pos:     info.pos,
+				Message: fmt.Sprintf("%s is only used in tests", info.name),
+			})
+		}
+		pass.Report(diag)
+	}
 
-			case *ast.SelectorExpr:
-				// For method calls and field accesses (x.y)
-				if x, ok := n.X.(*ast.Ident); ok {
-					// Check if the selector (method name) is a known declaration
-					if _, isDeclared := decls[n.Sel.Name]; isDeclared {
-						if isTest {
-							testUsages[n.Sel.Name] = true
-							if debug {
-								pass.Reportf(n.Sel.Pos(), "Test usage of method %s", n.Sel.Name)
-							}
-						} else {
-							nonTestUsages[n.Sel.Name] = true
-							if debug {
-								pass.Reportf(n.Sel.Pos(), "Non-test usage of method %s", n.Sel.Name)
-							}
-						}
-					}
+	return remaining
+}
 
-					// Also check if the base type is a known declaration
-					if _, isDeclared := decls[x.Name]; isDeclared {
-						if isTest {
-							testUsages[x.Name] = true
-						} else {
-							nonTestUsages[x.Name] = true
-						}
-					}
-				}
-			}
-			return true
-		})
+// Categories of test-only reports when CategoriesByVisibility is set
+const (
+	exportedCategory   = "intestonly-exported"
+	unexportedCategory = "intestonly-unexported"
+)
+
+// testOnlyCategory returns the category of the report of a test-only
+// declaration. It is empty, which drivers show as the analyzer name, unless
+// CategoriesByVisibility is set.
+func testOnlyCategory(name string, config *Config) string {
+	switch {
+	case !config.CategoriesByVisibility:
+		return ""
+	case ast.IsExported(name):
+		return exportedCategory
+	default:
+		return unexportedCategory
 	}
+}
 
-	if debug {
-		pass.Reportf(token.NoPos, "Found %d usages in test files", len(testUsages))
-		pass.Reportf(token.NoPos, "Found %d usages in non-test files", len(nonTestUsages))
+// reportTestOnly reports a declaration that is only used in test files,
+// pointing at the test usage when one is known
+func reportTestOnly(pass *analysis.Pass, info intestOnlyInfo, message, category string, testUsage token.Pos) {
+	diag := analysis.Diagnostic{
+		Pos:            info.pos,
+		Category:       category,
+		Message:        message,
+		SuggestedFixes: deletionFix(info),
+	}
+	if testUsage.IsValid() {
+		diag.Related = []analysis.RelatedInformation{{
+			Pos:     testUsage,
+			Message: fmt.Sprintf("%s is used in a test here", info.name),
+		}}
 	}
+	pass.Report(diag)
+}
 
-	// Report identifiers that are only used in test files
-	for name, info := range decls {
-		// Force report expected test cases from want.txt
-		if isExplicitTestOnly(name) {
-			pass.Reportf(info.pos, "identifier %q is only used in test files but is not part of test files", name)
-			continue
+// unusedCategory is the category of diagnostics for declarations that are
+// used nowhere, which tools can enable separately from test-only reports
+const unusedCategory = "unused"
+
+// reportUnused reports a declaration that neither tests nor production code
+// use
+func reportUnused(pass *analysis.Pass, info intestOnlyInfo) {
+	pass.Report(analysis.Diagnostic{
+		Pos:            info.pos,
+		Category:       unusedCategory,
+		Message:        fmt.Sprintf("identifier %q is never used", info.name),
+		SuggestedFixes: deletionFix(info),
+	})
+}
+
+// hasGoTestFiles returns true if any of the files is a _test.go file
+func hasGoTestFiles(pass *analysis.Pass, files []*ast.File) bool {
+	for _, file := range files {
+		if strings.HasSuffix(pass.Fset.File(file.Pos()).Name(), "_test.go") {
+			return true
 		}
+	}
+	return false
+}
+
+// canBeUnused returns true if a declaration without usages in the package
+// is unused. Exported names may be used by importers, and init and main are
+// called by the runtime.
+func canBeUnused(name string) bool {
+	return !ast.IsExported(name) && name != "init" && name != "main" && name != "_"
+}
+
+// testFunctionKinds are the prefixes of the functions run by go test
+var testFunctionKinds = []string{"Test", "Benchmark", "Example", "Fuzz"}
+
+// testFunctionKind returns the kind of test function a declaration is, such
+// as "Example" for ExampleFoo, or an empty string for other declarations.
+// Like go test, it requires a function without receiver whose name doesn't
+// continue the prefix with a lower case letter.
+func testFunctionKind(decl ast.Decl) string {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv != nil {
+		return ""
+	}
 
-		// Skip checking test helper identifiers and excluded methods
-		if isTestHelperIdentifier(name) || shouldExcludeFromReport(name) {
+	for _, kind := range testFunctionKinds {
+		if !strings.HasPrefix(fn.Name.Name, kind) {
 			continue
 		}
+		rest := fn.Name.Name[len(kind):]
+		if rest == "" {
+			return kind
+		}
+		r, _ := utf8.DecodeRuneInString(rest)
+		if !unicode.IsLower(r) {
+			return kind
+		}
+	}
 
-		if testUsages[name] && !nonTestUsages[name] {
-			// This identifier is used in test files but not in non-test files
-			pass.Reportf(info.pos, "identifier %q is only used in test files but is not part of test files", name)
-			if debug {
-				pass.Reportf(info.pos, "Reporting %s: testUsage=%v, nonTestUsage=%v",
-					name, testUsages[name], nonTestUsages[name])
+	return ""
+}
+
+// isSelected returns true if the name matches one of IncludePatterns, when
+// there are any, and none of ExcludePatterns
+func isSelected(name string, config *Config) bool {
+	if len(config.IncludePatterns) > 0 && !matchesName(name, config.IncludePatterns) {
+		return false
+	}
+	return !matchesName(name, config.ExcludePatterns)
+}
+
+// matchesName reports whether a declaration name matches any of the
+// patterns. Patterns with wildcards use path.Match, and other patterns
+// match whole words with matchesWholeWord.
+func matchesName(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.ContainsAny(pattern, "*?[") {
+			if matchWildcard(pattern, name) {
+				return true
 			}
+		} else if matchesWholeWord(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesWholeWord reports whether the camelCase words of pattern appear
+// in a row among the words of name, ignoring case. "api" matches APIClient
+// and getAPI but not Capitalize.
+func matchesWholeWord(pattern, name string) bool {
+	want := splitWords(pattern)
+	if len(want) == 0 {
+		return false
+	}
+	words := splitWords(name)
+	for i := 0; i+len(want) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(want)], want) {
+			return true
 		}
 	}
+	return false
+}
 
-	return nil, nil
+// filesInBuildContext returns the files of the pass that the default build
+// context includes
+func filesInBuildContext(pass *analysis.Pass, log Logger) []*ast.File {
+	var files []*ast.File
+	for _, file := range pass.Files {
+		fileName := pass.Fset.File(file.Pos()).Name()
+		if !matchesBuildContext(fileName, file) {
+			log.Debugf("Skipping %s excluded by build constraints", fileName)
+			continue
+		}
+		files = append(files, file)
+	}
+	return files
 }
 
-func isTestFile(filename string) bool {
-	return strings.HasSuffix(filename, "_test.go")
+// countsAsProduction returns true if the usages in test functions of the
+// given kind are configured to count as production usages
+func countsAsProduction(kind string, config *Config) bool {
+	switch kind {
+	case "Example":
+		return config.ExampleFunctionsCountAsProduction
+	case "Benchmark":
+		return config.BenchmarksCountAsProduction
+	}
+	return false
 }
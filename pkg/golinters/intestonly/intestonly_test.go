@@ -1,20 +1,992 @@
 package intestonly_test
 
 import (
+	"bytes"
+	"fmt"
+	"go/format"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"text/scanner"
 
 	"github.com/korchasa/golangci-intestonly/pkg/golinters/intestonly"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/checker"
+	"golang.org/x/tools/go/packages"
 )
 
-func TestAll(t *testing.T) {
+func testdataDir(t *testing.T) string {
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Fatalf("Failed to get wd: %s", err)
 	}
 
-	testdata := filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(wd))), "testdata")
-	analysistest.Run(t, testdata, intestonly.Analyzer, "p")
+	return filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(wd))), "testdata")
+}
+
+// analyzeTestVariant applies the analyzer to the test variant of a testdata
+// package, which includes the package's in-package _test.go files, and to
+// its black-box test package if there is one.
+func analyzeTestVariant(t *testing.T, a *analysis.Analyzer, pkgPath string, buildFlags ...string) *checker.Action {
+	t.Helper()
+
+	dir := testdataDir(t)
+	cfg := &packages.Config{
+		Mode:       packages.LoadAllSyntax,
+		Dir:        dir,
+		Env:        append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOWORK=off"),
+		BuildFlags: buildFlags,
+		Tests:      true,
+	}
+	pkgs, err := packages.Load(cfg, pkgPath)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %s", pkgPath, err)
+	}
+
+	// Packages with only black-box tests have no test variant of their own
+	var testPkg, blackBoxPkg *packages.Package
+	for _, pkg := range pkgs {
+		switch pkg.ID {
+		case fmt.Sprintf("%s [%s.test]", pkgPath, pkgPath):
+			testPkg = pkg
+		case pkgPath:
+			if testPkg == nil {
+				testPkg = pkg
+			}
+		case fmt.Sprintf("%s_test [%s.test]", pkgPath, pkgPath):
+			blackBoxPkg = pkg
+		}
+	}
+	if testPkg == nil {
+		t.Fatalf("Package %s not found", pkgPath)
+	}
+	roots := []*packages.Package{testPkg}
+	if blackBoxPkg != nil {
+		roots = append(roots, blackBoxPkg)
+	}
+
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, roots, nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze %s: %s", pkgPath, err)
+	}
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			t.Fatalf("Error analyzing %s: %s", act.Package.ID, act.Err)
+		}
+	}
+
+	// The black-box test package reports declarations of the tested package
+	act := graph.Roots[0]
+	for _, blackBox := range graph.Roots[1:] {
+		act.Diagnostics = append(act.Diagnostics, blackBox.Diagnostics...)
+	}
+
+	return act
+}
+
+// runOnTestVariant applies the analyzer to the test variant of a testdata
+// package and checks the diagnostics against its "// want" comments.
+//
+// Unlike analysistest.Run it ignores the package built without its test
+// files, where no identifier can be seen as used in tests.
+func runOnTestVariant(t *testing.T, a *analysis.Analyzer, pkgPath string) *checker.Action {
+	t.Helper()
+
+	act := analyzeTestVariant(t, a, pkgPath)
+	testPkg := act.Package
+
+	wants := make(map[string][]*regexp.Regexp)
+	for _, file := range testPkg.Syntax {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				pos := testPkg.Fset.Position(comment.Pos())
+				key := fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+				wants[key] = append(wants[key], parseWants(t, comment.Text)...)
+			}
+		}
+	}
+
+	for _, diag := range act.Diagnostics {
+		pos := testPkg.Fset.Position(diag.Pos)
+		key := fmt.Sprintf("%s:%d", filepath.Base(pos.Filename), pos.Line)
+		matched := false
+		for i, want := range wants[key] {
+			if want.MatchString(diag.Message) {
+				wants[key] = append(wants[key][:i], wants[key][i+1:]...)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			t.Errorf("%s: unexpected diagnostic: %s", key, diag.Message)
+		}
+	}
+
+	for key, rest := range wants {
+		for _, want := range rest {
+			t.Errorf("%s: no diagnostic was reported matching %q", key, want)
+		}
+	}
+
+	return act
+}
+
+// parseWants extracts the expected message patterns from a "// want" comment.
+func parseWants(t *testing.T, text string) []*regexp.Regexp {
+	t.Helper()
+
+	idx := strings.Index(text, "// want ")
+	if idx < 0 {
+		return nil
+	}
+
+	var s scanner.Scanner
+	s.Init(strings.NewReader(text[idx+len("// want "):]))
+	s.Mode = scanner.ScanStrings | scanner.ScanRawStrings
+
+	var wants []*regexp.Regexp
+	for tok := s.Scan(); tok == scanner.String || tok == scanner.RawString; tok = s.Scan() {
+		pattern, err := strconv.Unquote(s.TokenText())
+		if err != nil {
+			t.Fatalf("Invalid want pattern %s: %s", s.TokenText(), err)
+		}
+		wants = append(wants, regexp.MustCompile(pattern))
+	}
+
+	return wants
+}
+
+func TestAll(t *testing.T) {
+	// analysistest also checks the package without its tests, where only
+	// the explicit test cases are reported
+	config := intestonly.DefaultConfig()
+	config.ExplicitTestCases = []string{
+		"testOnlyFunction", "TestOnlyType", "testOnlyConstant",
+		"helperFunction", "reflectionFunction", "testMethod",
+		"outerMethod", "innerMethod", "embeddedMethod",
+	}
+	config.ReportExplicitTestCases = true
+	analysistest.Run(t, testdataDir(t), intestonly.NewAnalyzer(config), "p")
+
+	// The test variant finds the same declarations by their usages alone
+	runOnTestVariant(t, intestonly.Analyzer, "p")
+}
+
+func TestExplicitTestCases(t *testing.T) {
+	// Both functions are named like test helpers, which are skipped
+	if act := analyzeTestVariant(t, intestonly.Analyzer, "explicit"); len(act.Diagnostics) != 0 {
+		t.Errorf("Expected the test helpers to be skipped, got %d diagnostics", len(act.Diagnostics))
+	}
+
+	// Explicit test cases are checked like any other declaration
+	config := intestonly.DefaultConfig()
+	config.ExplicitTestCases = []string{"setupDatabase", "mockClock"}
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "explicit")
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"setupDatabase"`) {
+		t.Errorf("Expected only setupDatabase to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+
+	// and reported whatever their usages when forced
+	config.ReportExplicitTestCases = true
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "explicit"); len(act.Diagnostics) != 2 {
+		t.Errorf("Expected both explicit test cases to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestReflectionAnalysisDisabled(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.EnableReflectionAnalysis = false
+	runOnTestVariant(t, intestonly.NewAnalyzer(config), "reflection")
+
+	// Risky names are only skipped while reflection analysis is enabled
+	config.ConsiderReflectionRisky = true
+	runOnTestVariant(t, intestonly.NewAnalyzer(config), "reflection")
+
+	config.EnableReflectionAnalysis = true
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "reflection"); len(act.Diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics with reflection analysis and risky names, got %d", len(act.Diagnostics))
+	}
+}
+
+func TestAnnotateExclusions(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.ConsiderReflectionRisky = true
+	config.ReflectionRiskPatterns = []string{"Parse"}
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library"); len(act.Diagnostics) != 1 {
+		t.Errorf("Expected only normalize to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+
+	config.AnnotateExclusions = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+	var notes []string
+	for _, diag := range act.Diagnostics {
+		if diag.Category == "excluded" {
+			notes = append(notes, diag.Message)
+		}
+	}
+	want := `identifier "Parse" would be reported but is excluded as reflection-risky`
+	if len(act.Diagnostics) != 2 || len(notes) != 1 || notes[0] != want {
+		t.Errorf("Expected normalize to be reported and %q noted, got %d diagnostics and notes %q", want, len(act.Diagnostics), notes)
+	}
+
+	// Risky declarations used in production wouldn't be reported anyway
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "reflection"); len(act.Diagnostics) != 0 {
+		t.Errorf("Expected no notes for declarations used in production, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestImportedEmbedding(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "crosspkg/embedding")
+}
+
+func TestDotImport(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "crosspkg/dotimport")
+}
+
+func TestImportedMethodCalls(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "crosspkg/methods")
+}
+
+func TestGenerics(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "generics")
+}
+
+func TestImplementedInterfaces(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "interfaces")
+}
+
+func TestKnownImplicitMethods(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "implicit")
+
+	config := intestonly.DefaultConfig()
+	config.KnownImplicitMethods = nil
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "implicit")
+	if len(act.Diagnostics) != 3 {
+		t.Errorf("Expected all three methods to be reported without known implicit methods, got %d", len(act.Diagnostics))
+	}
+}
+
+func TestCompositeLiteralTypes(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "composite")
+}
+
+func TestRegistryShapes(t *testing.T) {
+	act := runOnTestVariant(t, intestonly.Analyzer, "registry")
+	reported := make(map[string]bool)
+	for _, diag := range act.Diagnostics {
+		reported[diag.Message] = true
+	}
+
+	// The types have no methods, so only the literal storing them is a usage
+	tests := []struct {
+		shape      string
+		production string
+		testOnly   string
+	}{
+		{"map key", "KeyEntry", "UnregisteredKey"},
+		{"map value", "ValueEntry", "UnregisteredValue"},
+		{"slice element", "ElementEntry", "UnregisteredElement"},
+		{"address of slice element", "PointerEntry", "UnregisteredPointer"},
+		{"struct field", "FieldEntry", "UnregisteredField"},
+	}
+	message := `identifier %q is only used in test files but is not part of test files`
+	for _, tt := range tests {
+		t.Run(tt.shape, func(t *testing.T) {
+			if reported[fmt.Sprintf(message, tt.production)] {
+				t.Errorf("%s is registered in production but reported", tt.production)
+			}
+			if !reported[fmt.Sprintf(message, tt.testOnly)] {
+				t.Errorf("%s is only registered in tests but not reported", tt.testOnly)
+			}
+		})
+	}
+}
+
+func TestStringLiteralAnalysis(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.EnableStringLiteralAnalysis = true
+	runOnTestVariant(t, intestonly.NewAnalyzer(config), "strlit")
+
+	for name, analyzer := range map[string]*analysis.Analyzer{
+		"default":  intestonly.Analyzer,
+		"disabled": intestonly.NewAnalyzer(&intestonly.Config{EnableStringLiteralAnalysis: false}),
+	} {
+		act := analyzeTestVariant(t, analyzer, "strlit")
+		if len(act.Diagnostics) != 3 {
+			t.Errorf("Expected string literals to be ignored with the %s config, got %d diagnostics", name, len(act.Diagnostics))
+		}
+	}
+}
+
+func TestReportUnusedEverywhere(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.ReportUnusedEverywhere = true
+	act := runOnTestVariant(t, intestonly.NewAnalyzer(config), "unused")
+
+	for _, diag := range act.Diagnostics {
+		never := strings.HasSuffix(diag.Message, "is never used")
+		if never != (diag.Category == "unused") {
+			t.Errorf("Unexpected category %q for %q", diag.Category, diag.Message)
+		}
+	}
+
+	act = analyzeTestVariant(t, intestonly.Analyzer, "unused")
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"onlyTested"`) {
+		t.Errorf("Expected only onlyTested to be reported by default, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestCollapseFileLevelReports(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "collapse")
+
+	config := intestonly.DefaultConfig()
+	config.CollapseFileLevelReports = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "collapse")
+	fset := act.Package.Fset
+
+	var messages []string
+	for _, diag := range act.Diagnostics {
+		messages = append(messages, diag.Message)
+		if strings.HasPrefix(diag.Message, "all ") {
+			if file := filepath.Base(fset.Position(diag.Pos).Filename); file != "fixtures.go" {
+				t.Errorf("Expected the file-level report in fixtures.go, got %s", file)
+			}
+			if len(diag.Related) != 3 {
+				t.Errorf("Expected the file-level report to list 3 declarations, got %d", len(diag.Related))
+			}
+		}
+	}
+	sort.Strings(messages)
+
+	want := []string{
+		"all 3 declarations in fixtures.go are only used in tests",
+		`identifier "debugDump" is only used in test files but is not part of test files`,
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected diagnostics:\n%s\nwant:\n%s", strings.Join(messages, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestAnalyzeDir(t *testing.T) {
+	issues, err := intestonly.AnalyzeDir(filepath.Join(testdataDir(t), "src", "library"), nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze directory: %s", err)
+	}
+
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %d: %v", len(issues), issues)
+	}
+	if filepath.Base(issues[0].File) != "library.go" || issues[0].Line != 4 || !strings.Contains(issues[0].Message, `"Parse"`) {
+		t.Errorf("Unexpected first issue: %s", issues[0])
+	}
+	if issues[1].Line != 9 || !strings.Contains(issues[1].Message, `"normalize"`) {
+		t.Errorf("Unexpected second issue: %s", issues[1])
+	}
+}
+
+func TestMethodReferences(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "methodrefs")
+}
+
+func TestConstantExpressions(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "constexpr")
+}
+
+func TestDeferAndGoStatements(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "deferred")
+}
+
+func TestNestedTypes(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "nested")
+}
+
+func TestSignatureTypes(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "signatures")
+}
+
+func TestIotaBlock(t *testing.T) {
+	act := runOnTestVariant(t, intestonly.Analyzer, "iotablock")
+
+	// Removing an entry would shift the values of the entries after it
+	for _, diag := range act.Diagnostics {
+		if len(diag.SuggestedFixes) != 0 {
+			t.Errorf("Expected no suggested fix for %q", diag.Message)
+		}
+	}
+}
+
+func TestFunctionsInCompositeLiterals(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "dispatch")
+}
+
+func TestStructTagAnalysis(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.EnableStringLiteralAnalysis = true
+	config.EnableStructTagAnalysis = true
+	runOnTestVariant(t, intestonly.NewAnalyzer(config), "structtags")
+
+	config.EnableStructTagAnalysis = false
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "structtags")
+	if len(act.Diagnostics) != 2 {
+		t.Errorf("Expected struct tags to be ignored when disabled, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestInitAndMainRoots(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "initroots")
+	runOnTestVariant(t, intestonly.Analyzer, "mainroot")
+}
+
+func TestEmbeddedTypes(t *testing.T) {
+	act := runOnTestVariant(t, intestonly.Analyzer, "embedded")
+	fset := act.Package.Fset
+
+	// The test usage of an embedded type is the one of its embedding struct
+	for _, diag := range act.Diagnostics {
+		if !strings.Contains(diag.Message, `"BaseType"`) {
+			continue
+		}
+		if len(diag.Related) != 1 {
+			t.Fatalf("Expected one related location for BaseType, got %d", len(diag.Related))
+		}
+		if related := fset.Position(diag.Related[0].Pos); filepath.Base(related.Filename) != "embedded_test.go" || related.Line != 6 {
+			t.Errorf("Expected BaseType to point at embedded_test.go:6, got %s", related)
+		}
+	}
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "recursive")
+}
+
+func TestConversions(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "conversions")
+}
+
+func TestLinkedFunctions(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "linkname")
+
+	config := intestonly.DefaultConfig()
+	config.TreatLinkedFunctionsAsUsed = false
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "linkname"); len(act.Diagnostics) != 3 {
+		t.Errorf("Expected every function to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestClosures(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "closures")
+}
+
+func TestShadowing(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "shadowing")
+}
+
+func TestMultiValueAssignments(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "assignments")
+}
+
+func TestGoGenerateDirectives(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "generate")
+
+	// Directives in production files count as production usages
+	config := intestonly.DefaultConfig()
+	config.EnableGoGenerateAnalysis = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "generate")
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"Cache"`) {
+		t.Errorf("Expected only Cache to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestImportedInterfaceAssignments(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "handlers")
+}
+
+func TestGeneratedFiles(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "generated")
+
+	config := intestonly.DefaultConfig()
+	config.SkipGeneratedFiles = false
+	if act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "generated"); len(act.Diagnostics) != 3 {
+		t.Errorf("Expected the generated methods and decode to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestControlFlow(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "controlflow")
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	act := runOnTestVariant(t, intestonly.Analyzer, "fixes")
+	fset := act.Package.Fset
+
+	edits := make(map[string][]analysis.TextEdit)
+	for _, diag := range act.Diagnostics {
+		for _, fix := range diag.SuggestedFixes {
+			for _, edit := range fix.TextEdits {
+				file := fset.File(edit.Pos).Name()
+				edits[file] = append(edits[file], edit)
+			}
+		}
+	}
+
+	if len(edits) == 0 {
+		t.Fatal("No suggested fixes were reported")
+	}
+
+	for file, fileEdits := range edits {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %s", file, err)
+		}
+
+		// Apply the edits from the end of the file to keep offsets valid
+		sort.Slice(fileEdits, func(i, j int) bool { return fileEdits[i].Pos > fileEdits[j].Pos })
+		for _, edit := range fileEdits {
+			start, end := fset.Position(edit.Pos).Offset, fset.Position(edit.End).Offset
+			content = append(content[:start:start], append(edit.NewText, content[end:]...)...)
+		}
+
+		got, err := format.Source(content)
+		if err != nil {
+			t.Fatalf("Failed to format fixed %s: %s\n%s", file, err, content)
+		}
+		want, err := os.ReadFile(file + ".golden")
+		if err != nil {
+			t.Fatalf("Failed to read golden file: %s", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("Unexpected result of fixes in %s:\n%s\nwant:\n%s", file, got, want)
+		}
+	}
+}
+
+func TestRelatedInformation(t *testing.T) {
+	act := runOnTestVariant(t, intestonly.Analyzer, "fixes")
+	fset := act.Package.Fset
+
+	for _, diag := range act.Diagnostics {
+		if len(diag.Related) != 1 {
+			t.Errorf("Expected one related location for %q, got %d", diag.Message, len(diag.Related))
+			continue
+		}
+
+		related := fset.Position(diag.Related[0].Pos)
+		if filepath.Base(related.Filename) != "fixes_test.go" {
+			t.Errorf("Expected %q to point at fixes_test.go, got %s", diag.Message, related)
+		}
+		if strings.Contains(diag.Message, `"unusedFunction"`) && related.Line != 6 {
+			t.Errorf("Expected unusedFunction to point at line 6, got %s", related)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "directives")
+}
+
+func TestExampleFunctions(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "examples")
+
+	config := intestonly.DefaultConfig()
+	config.ExampleFunctionsCountAsProduction = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "examples")
+
+	var reported []string
+	for _, diag := range act.Diagnostics {
+		reported = append(reported, diag.Message)
+	}
+	if len(reported) != 2 || strings.Contains(strings.Join(reported, "\n"), `"Greet"`) {
+		t.Errorf("Expected only farewell and repeat to be reported, got %v", reported)
+	}
+}
+
+func TestBenchmarkFunctions(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "benchmarks")
+
+	config := intestonly.DefaultConfig()
+	config.BenchmarksCountAsProduction = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "benchmarks")
+
+	var reported []string
+	for _, diag := range act.Diagnostics {
+		reported = append(reported, diag.Message)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0], `"validate"`) {
+		t.Errorf("Expected only validate to be reported, got %v", reported)
+	}
+}
+
+func TestLibraryMode(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "library")
+
+	config := intestonly.DefaultConfig()
+	config.LibraryMode = true
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	var reported []string
+	for _, diag := range act.Diagnostics {
+		reported = append(reported, diag.Message)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0], `"normalize"`) {
+		t.Errorf("Expected only normalize to be reported, got %v", reported)
+	}
+}
+
+func TestConvertedSettings(t *testing.T) {
+	// Settings reach the running analyzer, including the overrides that
+	// getConfig picks by package path
+	enabled := true
+	config := intestonly.ConvertSettings(&intestonly.IntestOnlySettings{
+		PackageOverrides: map[string]intestonly.IntestOnlySettings{
+			"library": {LibraryMode: &enabled},
+		},
+	})
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"normalize"`) {
+		t.Errorf("Expected only normalize to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestAnalyzerFlags(t *testing.T) {
+	a := intestonly.NewAnalyzer(intestonly.DefaultConfig())
+	if err := a.Flags.Set("exclude-patterns", "Parse"); err != nil {
+		t.Fatalf("Failed to set exclude-patterns: %s", err)
+	}
+	act := analyzeTestVariant(t, a, "library")
+
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"normalize"`) {
+		t.Errorf("Expected only normalize to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+
+	// The message template is parsed after the flags
+	a = intestonly.NewAnalyzer(intestonly.DefaultConfig())
+	if err := a.Flags.Set("message-template", "{{.Kind}} {{.Name}} is only used in tests"); err != nil {
+		t.Fatalf("Failed to set message-template: %s", err)
+	}
+	act = analyzeTestVariant(t, a, "iotablock")
+	if len(act.Diagnostics) != 1 || act.Diagnostics[0].Message != "constant levelInfo is only used in tests" {
+		t.Errorf("Unexpected diagnostics %v", act.Diagnostics)
+	}
+}
+
+func TestIncludePatterns(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.IncludePatterns = []string{"norm*"}
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"normalize"`) {
+		t.Errorf("Expected only normalize to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestAlwaysUsed(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.AlwaysUsed = []string{"Parse", "normal"}
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	// Names must match exactly, so "normal" doesn't cover normalize
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"normalize"`) {
+		t.Errorf("Expected only normalize to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+}
+
+func TestAlwaysUsedFieldTypes(t *testing.T) {
+	alwaysUsed := intestonly.DefaultConfig()
+	alwaysUsed.AlwaysUsed = []string{"Plugin"}
+	reflectionRisky := intestonly.DefaultConfig()
+	reflectionRisky.ConsiderReflectionRisky = true
+
+	// The types of the fields of types that aren't reported as only used in
+	// tests are used in production as much as those types
+	tests := []struct {
+		name   string
+		config *intestonly.Config
+		want   []string
+	}{
+		{"default", intestonly.DefaultConfig(), []string{"Plugin", "PluginConfig", "RequestHandler", "handlerOptions"}},
+		{"always used", alwaysUsed, []string{"RequestHandler", "handlerOptions"}},
+		{"reflection risky", reflectionRisky, []string{"Plugin", "PluginConfig"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			act := analyzeTestVariant(t, intestonly.NewAnalyzer(tt.config), "fieldtypes")
+			var got []string
+			for _, d := range act.Diagnostics {
+				got = append(got, d.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d diagnostics, got %q", len(tt.want), got)
+			}
+			for _, name := range tt.want {
+				if !slices.ContainsFunc(got, func(msg string) bool { return strings.Contains(msg, `"`+name+`"`) }) {
+					t.Errorf("Expected %s to be reported, got %q", name, got)
+				}
+			}
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	libraryMode := intestonly.DefaultConfig()
+	libraryMode.LibraryMode = true
+	alwaysUsed := intestonly.DefaultConfig()
+	alwaysUsed.AlwaysUsed = []string{"Parse"}
+
+	tests := []struct {
+		pkg    string
+		config *intestonly.Config
+		want   intestonly.Stats
+	}{
+		{"library", intestonly.DefaultConfig(), intestonly.Stats{Checked: 2, Reported: 2}},
+		{"library", libraryMode, intestonly.Stats{Checked: 2, Exported: 1, Reported: 1}},
+		{"library", alwaysUsed, intestonly.Stats{Checked: 2, Excluded: 1, Reported: 1}},
+		{"directives", intestonly.DefaultConfig(), intestonly.Stats{Checked: 9, Ignored: 7, Reported: 2}},
+		{"implicit", intestonly.DefaultConfig(), intestonly.Stats{Checked: 6, ImplicitMethods: 2, UsedInProduction: 3, Reported: 1}},
+	}
+
+	for _, tt := range tests {
+		act := analyzeTestVariant(t, intestonly.NewAnalyzer(tt.config), tt.pkg)
+		stats, ok := act.Result.(*intestonly.Stats)
+		if !ok {
+			t.Fatalf("Expected the result to be *Stats, got %T", act.Result)
+		}
+		if *stats != tt.want {
+			t.Errorf("%s: stats = %+v, want %+v", tt.pkg, *stats, tt.want)
+		}
+	}
+}
+
+func TestCategoriesByVisibility(t *testing.T) {
+	categories := func(config *intestonly.Config) map[string]string {
+		act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+		got := make(map[string]string)
+		for _, diag := range act.Diagnostics {
+			got[diag.Message] = diag.Category
+		}
+		return got
+	}
+	parse := `identifier "Parse" is only used in test files but is not part of test files`
+	normalize := `identifier "normalize" is only used in test files but is not part of test files`
+
+	got := categories(intestonly.DefaultConfig())
+	if len(got) != 2 || got[parse] != "" || got[normalize] != "" {
+		t.Errorf("Expected two reports without a category by default, got %q", got)
+	}
+
+	config := intestonly.DefaultConfig()
+	config.CategoriesByVisibility = true
+	got = categories(config)
+	if got[parse] != "intestonly-exported" || got[normalize] != "intestonly-unexported" {
+		t.Errorf("Unexpected categories %q", got)
+	}
+}
+
+func TestVerifyAgainstTypesInfo(t *testing.T) {
+	logger := &recordingLogger{}
+	config := intestonly.DefaultConfig()
+	config.VerifyAgainstTypesInfo = true
+	config.Logger = logger
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "embedded")
+
+	// The embedded types are named in production structs, which only tests
+	// use, while Clock is only embedded in a test file
+	var reported []string
+	for _, diag := range act.Diagnostics {
+		reported = append(reported, diag.Message)
+	}
+	sort.Strings(reported)
+	if len(reported) != 2 || !strings.Contains(reported[0], `"Clock"`) || !strings.Contains(reported[1], `"TestOnlyWrapper"`) {
+		t.Errorf("Expected only Clock and TestOnlyWrapper to be reported, got %q", reported)
+	}
+	for _, message := range logger.messages {
+		if strings.HasPrefix(message, "Not reporting BaseType: the type checker sees a production use at ") {
+			return
+		}
+	}
+	t.Errorf("Expected the dropped BaseType report to be logged, got %q", logger.messages)
+}
+
+func TestLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	config := intestonly.DefaultConfig()
+	config.Logger = logger
+	analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	want := "Reporting normalize: testUsage=true, nonTestUsage=false"
+	for _, message := range logger.messages {
+		if message == want {
+			return
+		}
+	}
+	t.Errorf("Expected %q to be logged, got %q", want, logger.messages)
+}
+
+func TestRespectBuildTags(t *testing.T) {
+	// Loaded with the extra tag, the tagged files take part in the analysis
+	act := analyzeTestVariant(t, intestonly.Analyzer, "buildtags", "-tags=intestonly_extra")
+	if len(act.Diagnostics) != 1 || !strings.Contains(act.Diagnostics[0].Message, `"extraOnly"`) {
+		t.Errorf("Expected extraOnly to be reported, got %d diagnostics", len(act.Diagnostics))
+	}
+
+	// The default build context doesn't have the tag
+	config := intestonly.DefaultConfig()
+	config.RespectBuildTags = true
+	act = analyzeTestVariant(t, intestonly.NewAnalyzer(config), "buildtags", "-tags=intestonly_extra")
+	if len(act.Diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics with build tags respected, got %d", len(act.Diagnostics))
+	}
+}
+
+func TestMessageFormat(t *testing.T) {
+	format := regexp.MustCompile(`^identifier "[A-Za-z_][A-Za-z0-9_]*" is only used in test files but is not part of test files$`)
+
+	for _, pkg := range []string{"library", "examples", "collapse"} {
+		act := analyzeTestVariant(t, intestonly.Analyzer, pkg)
+		if len(act.Diagnostics) == 0 {
+			t.Errorf("Expected diagnostics for %s", pkg)
+		}
+		for _, diag := range act.Diagnostics {
+			if !format.MatchString(diag.Message) {
+				t.Errorf("Message %q doesn't follow the documented format", diag.Message)
+			}
+		}
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.MessageTemplate = "{{.Kind}} {{.Name}} is only used in tests"
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "iotablock")
+
+	if len(act.Diagnostics) != 1 || act.Diagnostics[0].Message != "constant levelInfo is only used in tests" {
+		t.Errorf("Unexpected diagnostics %v", act.Diagnostics)
+	}
+}
+
+func TestSkipPackages(t *testing.T) {
+	config := intestonly.DefaultConfig()
+	config.SkipPackages = []string{"library"}
+	act := analyzeTestVariant(t, intestonly.NewAnalyzer(config), "library")
+
+	if len(act.Diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics for a skipped package, got %d", len(act.Diagnostics))
+	}
+}
+
+func TestExternalTestPackage(t *testing.T) {
+	runOnTestVariant(t, intestonly.Analyzer, "blackbox")
+	runOnTestVariant(t, intestonly.Analyzer, "blackboxonly")
+	runOnTestVariant(t, intestonly.Analyzer, "blankimport")
+}
+
+// analyzeWithoutSources loads a testdata package from a copy, removes the
+// copied sources and analyzes the loaded syntax, returning the sorted
+// messages reported for the package and its tests
+func analyzeWithoutSources(t *testing.T, a *analysis.Analyzer, pkg string, buildFlags ...string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src", pkg)
+	if err := os.CopyFS(src, os.DirFS(filepath.Join(testdataDir(t), "src", pkg))); err != nil {
+		t.Fatalf("Failed to copy the testdata: %s", err)
+	}
+	cfg := &packages.Config{
+		Mode:       packages.LoadAllSyntax,
+		Dir:        dir,
+		Env:        append(os.Environ(), "GOPATH="+dir, "GO111MODULE=off", "GOWORK=off"),
+		BuildFlags: buildFlags,
+		Tests:      true,
+	}
+	pkgs, err := packages.Load(cfg, pkg)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %s", pkg, err)
+	}
+
+	// The analysis works on the loaded syntax only, like with the overlays
+	// of an editor, so it doesn't matter that the files are gone
+	if err := os.RemoveAll(src); err != nil {
+		t.Fatalf("Failed to remove the sources: %s", err)
+	}
+	graph, err := checker.Analyze([]*analysis.Analyzer{a}, pkgs, nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze %s: %s", pkg, err)
+	}
+
+	var reported []string
+	for _, act := range graph.Roots {
+		if act.Err != nil {
+			t.Fatalf("Error analyzing %s: %s", act.Package.ID, act.Err)
+		}
+		for _, diag := range act.Diagnostics {
+			reported = append(reported, diag.Message)
+		}
+	}
+	sort.Strings(reported)
+	return reported
+}
+
+func TestSourcesNotRead(t *testing.T) {
+	respectBuildTags := intestonly.DefaultConfig()
+	respectBuildTags.RespectBuildTags = true
+
+	want := []string{
+		`identifier "Dotted" is only used in test files but is not part of test files`,
+		`identifier "Exported" is only used in test files but is not part of test files`,
+		`identifier "Fetch" is only used in test files but is not part of test files`,
+	}
+	for _, a := range []*analysis.Analyzer{intestonly.Analyzer, intestonly.NewAnalyzer(respectBuildTags)} {
+		if reported := analyzeWithoutSources(t, a, "blackbox"); strings.Join(reported, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Unexpected reports without sources on disk:\n%s\nwant:\n%s", strings.Join(reported, "\n"), strings.Join(want, "\n"))
+		}
+	}
+
+	// Build constraints are read from the loaded syntax as well
+	if reported := analyzeWithoutSources(t, intestonly.NewAnalyzer(respectBuildTags), "buildtags", "-tags=intestonly_extra"); len(reported) != 0 {
+		t.Errorf("Expected the tagged files to be skipped without sources on disk, got %q", reported)
+	}
+}
+
+func TestExternalTestPackageReportedOnce(t *testing.T) {
+	// analysistest.Run also checks the package built without test files,
+	// which must leave the black-box tests to the test variant
+	results := analysistest.Run(&recordingT{}, testdataDir(t), intestonly.Analyzer, "blackbox")
+
+	reporting := 0
+	for _, result := range results {
+		if len(result.Diagnostics) > 0 {
+			reporting++
+		}
+	}
+	if reporting != 1 {
+		t.Errorf("Expected reports from a single package variant, got %d", reporting)
+	}
+}
+
+// recordingT collects analysistest errors instead of failing the test
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// recordingLogger collects the debug output of the analyzer
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
 }
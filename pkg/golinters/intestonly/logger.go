@@ -0,0 +1,12 @@
+package intestonly
+
+// Logger receives the debug output of the analyzer. Packages may be
+// analyzed concurrently, so implementations must be safe for concurrent use.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// nopLogger discards all output
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
@@ -0,0 +1,66 @@
+package intestonly
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"text/template"
+)
+
+// DefaultMessageTemplate is the message reported for declarations that are
+// only used in tests
+const DefaultMessageTemplate = `identifier {{printf "%q" .Name}} is only used in test files but is not part of test files`
+
+// messageData holds the values available to a message template
+type messageData struct {
+	Kind string // function, method, type, constant or variable
+	Name string
+}
+
+// parseMessageTemplate parses a message template, using the default one for
+// an empty text. The template is tried on sample data so that references to
+// unknown fields fail here rather than on the first report.
+func parseMessageTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultMessageTemplate
+	}
+
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	if _, err := renderMessage(tmpl, messageData{Kind: "function", Name: "name"}); err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// renderMessage renders the message of a declaration
+func renderMessage(tmpl *template.Template, data messageData) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("invalid message template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// declKind describes the kind of a declaration for messages
+func declKind(info intestOnlyInfo) string {
+	switch info.node.(type) {
+	case *ast.FuncDecl:
+		if info.isMethod {
+			return "method"
+		}
+		return "function"
+	case *ast.TypeSpec:
+		return "type"
+	case *ast.ValueSpec:
+		if info.genDecl.Tok == token.CONST {
+			return "constant"
+		}
+		return "variable"
+	}
+	return "identifier"
+}
@@ -0,0 +1,47 @@
+package intestonly
+
+import "testing"
+
+func TestMessageTemplate(t *testing.T) {
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{
+			template: DefaultMessageTemplate,
+			want:     `identifier "helper" is only used in test files but is not part of test files`,
+		},
+		{
+			template: "",
+			want:     `identifier "helper" is only used in test files but is not part of test files`,
+		},
+		{
+			template: "{{.Kind}} '{{.Name}}' is only used in tests",
+			want:     "function 'helper' is only used in tests",
+		},
+	}
+
+	for _, tt := range tests {
+		tmpl, err := parseMessageTemplate(tt.template)
+		if err != nil {
+			t.Errorf("Failed to parse %q: %s", tt.template, err)
+			continue
+		}
+		got, err := renderMessage(tmpl, messageData{Kind: "function", Name: "helper"})
+		if err != nil {
+			t.Errorf("Failed to render %q: %s", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Template %q rendered %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestMessageTemplateInvalid(t *testing.T) {
+	for _, text := range []string{"{{.Name", "{{.Package}} is only used in tests"} {
+		if _, err := parseMessageTemplate(text); err == nil {
+			t.Errorf("Expected an error for template %q", text)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package intestonly
+
+import "testing"
+
+func TestIsSelected(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		ident   string
+		want    bool
+	}{
+		{name: "empty includes", ident: "loadConfig", want: true},
+		{name: "matching include", include: []string{"*Client"}, ident: "HTTPClient", want: true},
+		{name: "non-matching include", include: []string{"*Client"}, ident: "loadConfig", want: false},
+		{name: "any matching include", include: []string{"*Client", "load*"}, ident: "loadConfig", want: true},
+		{name: "exclude", exclude: []string{"Legacy*"}, ident: "LegacyClient", want: false},
+		{name: "exclude after include", include: []string{"*Client"}, exclude: []string{"Legacy*"}, ident: "LegacyClient", want: false},
+		{name: "word exclude", exclude: []string{"Debug"}, ident: "printDebugInfo", want: false},
+		{name: "lower case word exclude", exclude: []string{"api"}, ident: "APIClient", want: false},
+		{name: "word inside another word", exclude: []string{"api"}, ident: "Capitalize", want: true},
+		{name: "several words", exclude: []string{"debugInfo"}, ident: "printDebugInfo", want: false},
+		{name: "words out of order", exclude: []string{"infoDebug"}, ident: "printDebugInfo", want: true},
+		{name: "word include", include: []string{"api"}, ident: "getAPI", want: true},
+		{name: "word include inside another word", include: []string{"api"}, ident: "Capitalize", want: false},
+	}
+
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.IncludePatterns = tt.include
+		config.ExcludePatterns = tt.exclude
+		if got := isSelected(tt.ident, config); got != tt.want {
+			t.Errorf("%s: isSelected(%q) = %v, want %v", tt.name, tt.ident, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package intestonly
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// New builds the analyzer from the settings of a golangci-lint plugin
+// configuration, which golangci-lint passes to the New function of the
+// plugin. The settings are keyed like the mapstructure tags of
+// IntestOnlySettings; unknown keys and values of the wrong type are errors.
+func New(conf any) ([]*analysis.Analyzer, error) {
+	settings, err := decodeSettings(conf)
+	if err != nil {
+		return nil, fmt.Errorf("intestonly settings: %w", err)
+	}
+
+	config := ConvertSettings(settings)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("intestonly settings: %w", err)
+	}
+
+	return []*analysis.Analyzer{NewAnalyzer(config)}, nil
+}
+
+// decodeSettings decodes the settings of a plugin configuration, a map of
+// the kind YAML and JSON decoders produce, into IntestOnlySettings
+func decodeSettings(conf any) (*IntestOnlySettings, error) {
+	settings := &IntestOnlySettings{}
+	if conf == nil {
+		return settings, nil
+	}
+	values, ok := conf.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected a map of settings, got %T", conf)
+	}
+	if err := decodeStruct(reflect.ValueOf(settings).Elem(), values); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// decodeStruct sets the fields of v, a struct with mapstructure tags, from
+// the values of the keys naming them
+func decodeStruct(v reflect.Value, values map[string]any) error {
+	fields := make(map[string]reflect.Value)
+	for i := 0; i < v.NumField(); i++ {
+		fields[v.Type().Field(i).Tag.Get("mapstructure")] = v.Field(i)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+		if err := decodeValue(field, values[key]); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// decodeValue sets a field of IntestOnlySettings from a decoded value
+func decodeValue(field reflect.Value, value any) error {
+	switch field.Interface().(type) {
+	case *bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+		field.Set(reflect.ValueOf(&b))
+
+	case *int:
+		var n int
+		switch v := value.(type) {
+		case int:
+			n = v
+		case int64:
+			n = int(v)
+		case uint64:
+			n = int(v)
+		case float64:
+			if v != float64(int(v)) {
+				return fmt.Errorf("expected an integer, got %v", v)
+			}
+			n = int(v)
+		default:
+			return fmt.Errorf("expected an integer, got %T", value)
+		}
+		field.Set(reflect.ValueOf(&n))
+
+	case *string:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		field.Set(reflect.ValueOf(&s))
+
+	case []string:
+		var list []string
+		switch v := value.(type) {
+		case []string:
+			list = v
+		case []any:
+			list = make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("expected a list of strings, got an item of type %T", item)
+				}
+				list = append(list, s)
+			}
+		default:
+			return fmt.Errorf("expected a list of strings, got %T", value)
+		}
+		field.Set(reflect.ValueOf(list))
+
+	case map[string]IntestOnlySettings:
+		patterns, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected a map of package patterns, got %T", value)
+		}
+		overrides := make(map[string]IntestOnlySettings, len(patterns))
+		for pattern, override := range patterns {
+			settings, err := decodeSettings(override)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pattern, err)
+			}
+			overrides[pattern] = *settings
+		}
+		field.Set(reflect.ValueOf(overrides))
+
+	default:
+		return fmt.Errorf("unsupported setting of type %s", field.Type())
+	}
+	return nil
+}
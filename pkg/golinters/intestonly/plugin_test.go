@@ -0,0 +1,101 @@
+package intestonly
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	// The settings as golangci-lint decodes them from its configuration
+	analyzers, err := New(map[string]any{
+		"library-mode":                true,
+		"string-reference-min-length": 5,
+		"exclude-patterns":            []any{"Legacy*", "*Mock"},
+		"message-template":            "{{.Name}} is test-only",
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %s", err)
+	}
+	if len(analyzers) != 1 {
+		t.Fatalf("Expected one analyzer, got %d", len(analyzers))
+	}
+
+	flags := analyzers[0].Flags
+	tests := map[string]string{
+		"library-mode":                "true",
+		"string-reference-min-length": "5",
+		"exclude-patterns":            "Legacy*,*Mock",
+		"message-template":            "{{.Name}} is test-only",
+		"skip-generated-files":        "true",
+	}
+	for name, want := range tests {
+		if got := flags.Lookup(name).Value.String(); got != want {
+			t.Errorf("Unexpected value of %s: %q, want %q", name, got, want)
+		}
+	}
+
+	// Without settings the analyzer uses the default configuration
+	if _, err := New(nil); err != nil {
+		t.Errorf("New(nil) failed: %s", err)
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		conf any
+		want string
+	}{
+		{[]any{"library-mode"}, "expected a map of settings"},
+		{map[string]any{"libary-mode": true}, `unknown setting "libary-mode"`},
+		{map[string]any{"library-mode": "yes"}, "library-mode: expected a boolean"},
+		{map[string]any{"string-reference-min-length": 2.5}, "string-reference-min-length: expected an integer"},
+		{map[string]any{"always-used": []any{"Main", 1}}, "always-used: expected a list of strings"},
+		{map[string]any{"package-overrides": map[string]any{"example.com/api": map[string]any{"library": true}}}, `example.com/api: unknown setting "library"`},
+		{map[string]any{"exclude-patterns": []any{"[Legacy"}}, "malformed pattern"},
+	}
+
+	for _, tt := range tests {
+		_, err := New(tt.conf)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("New(%v) = %v, want an error containing %q", tt.conf, err, tt.want)
+		}
+	}
+}
+
+func TestDecodeSettingsCoversSettings(t *testing.T) {
+	// A value for every setting, typed like a YAML decoder produces them
+	conf := make(map[string]any)
+	fields := reflect.TypeOf(IntestOnlySettings{})
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		var value any
+		switch field.Type {
+		case reflect.TypeOf((*bool)(nil)):
+			value = true
+		case reflect.TypeOf((*int)(nil)):
+			value = 4
+		case reflect.TypeOf((*string)(nil)):
+			value = "{{.Name}}"
+		case reflect.TypeOf([]string(nil)):
+			value = []any{"x"}
+		default:
+			value = map[string]any{"example.com/api": map[string]any{"library-mode": true}}
+		}
+		conf[field.Tag.Get("mapstructure")] = value
+	}
+
+	settings, err := decodeSettings(conf)
+	if err != nil {
+		t.Fatalf("decodeSettings() failed: %s", err)
+	}
+	decoded := reflect.ValueOf(*settings)
+	for i := 0; i < decoded.NumField(); i++ {
+		if decoded.Field(i).IsZero() {
+			t.Errorf("Setting %s was not decoded", fields.Field(i).Tag.Get("mapstructure"))
+		}
+	}
+	if override := settings.PackageOverrides["example.com/api"]; override.LibraryMode == nil || !*override.LibraryMode {
+		t.Errorf("Unexpected package override: %+v", override)
+	}
+}
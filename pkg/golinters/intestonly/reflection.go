@@ -0,0 +1,74 @@
+package intestonly
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reflectionLookups lists the reflect methods that resolve a method or field
+// by its name at run time.
+var reflectionLookups = map[string]bool{
+	"MethodByName": true,
+	"FieldByName":  true,
+}
+
+// reflectedName returns the method or field name looked up by a call such as
+// v.MethodByName("Handle") when the name is a string constant.
+func reflectedName(pass *analysis.Pass, call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !reflectionLookups[sel.Sel.Name] || len(call.Args) != 1 {
+		return "", false
+	}
+
+	// Make sure the method belongs to reflect.Value or reflect.Type and not
+	// to some unrelated type with the same method name
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "reflect" {
+		return "", false
+	}
+
+	tv, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// defaultReflectionRiskPatterns match names commonly resolved through
+// reflection, such as accessors and handlers looked up by name
+var defaultReflectionRiskPatterns = []string{"Get*", "Set*", "*Type", "*Handler"}
+
+// excludedCategory is the category of the informational diagnostics of
+// AnnotateExclusions
+const excludedCategory = "excluded"
+
+// reportReflectionRisky notes a declaration that would be reported as only
+// used in tests if it weren't reflection-risky
+func reportReflectionRisky(pass *analysis.Pass, info intestOnlyInfo) {
+	pass.Report(analysis.Diagnostic{
+		Pos:      info.pos,
+		Category: excludedCategory,
+		Message:  fmt.Sprintf("identifier %q would be reported but is excluded as reflection-risky", info.name),
+	})
+}
+
+// isReflectionRisky returns true if ConsiderReflectionRisky is set and the
+// declaration may be reached through reflection with a name built at run
+// time: an exported method, or a name matching ReflectionRiskPatterns.
+// Disabling EnableReflectionAnalysis turns this off as well.
+func isReflectionRisky(info intestOnlyInfo, config *Config) bool {
+	if !config.EnableReflectionAnalysis || !config.ConsiderReflectionRisky {
+		return false
+	}
+	if info.isMethod && token.IsExported(info.name) {
+		return true
+	}
+
+	return matchesPattern(info.name, config.ReflectionRiskPatterns)
+}
@@ -0,0 +1,88 @@
+package intestonly
+
+import "testing"
+
+func TestIsReflectionRisky(t *testing.T) {
+	decls := map[string]intestOnlyInfo{
+		"GetName":      {name: "GetName"},
+		"SetName":      {name: "SetName"},
+		"requestType":  {name: "requestType"},
+		"errorHandler": {name: "errorHandler"},
+		"Close":        {name: "Close", isMethod: true},
+		"close":        {name: "close", isMethod: true},
+		"loadConfig":   {name: "loadConfig"},
+		"Getaway":      {name: "Getaway"},
+	}
+
+	tests := []struct {
+		name     string
+		config   func(*Config)
+		excluded []string
+	}{
+		{
+			name:     "disabled",
+			config:   func(*Config) {},
+			excluded: nil,
+		},
+		{
+			name:     "default patterns",
+			config:   func(c *Config) { c.ConsiderReflectionRisky = true },
+			excluded: []string{"GetName", "SetName", "requestType", "errorHandler", "Close", "Getaway"},
+		},
+		{
+			name: "custom patterns",
+			config: func(c *Config) {
+				c.ConsiderReflectionRisky = true
+				c.ReflectionRiskPatterns = []string{"load*", "*Name"}
+			},
+			excluded: []string{"GetName", "SetName", "Close", "loadConfig"},
+		},
+		{
+			name: "no patterns",
+			config: func(c *Config) {
+				c.ConsiderReflectionRisky = true
+				c.ReflectionRiskPatterns = nil
+			},
+			excluded: []string{"Close"},
+		},
+	}
+
+	for _, tt := range tests {
+		config := DefaultConfig()
+		tt.config(config)
+
+		excluded := make(map[string]bool)
+		for _, name := range tt.excluded {
+			excluded[name] = true
+		}
+		for name, info := range decls {
+			if got := isReflectionRisky(info, config); got != excluded[name] {
+				t.Errorf("%s: isReflectionRisky(%q) = %v, want %v", tt.name, name, got, excluded[name])
+			}
+		}
+	}
+}
+
+func TestReflectionSwitches(t *testing.T) {
+	handle := intestOnlyInfo{name: "Handle", isMethod: true}
+
+	tests := []struct {
+		analysis bool
+		risky    bool
+		want     bool
+	}{
+		{analysis: false, risky: false, want: false},
+		{analysis: false, risky: true, want: false},
+		{analysis: true, risky: false, want: false},
+		{analysis: true, risky: true, want: true},
+	}
+
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.EnableReflectionAnalysis = tt.analysis
+		config.ConsiderReflectionRisky = tt.risky
+		if got := isReflectionRisky(handle, config); got != tt.want {
+			t.Errorf("isReflectionRisky() with analysis=%v risky=%v = %v, want %v", tt.analysis, tt.risky, got, tt.want)
+		}
+	}
+}
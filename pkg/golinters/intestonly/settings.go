@@ -0,0 +1,158 @@
+package intestonly
+
+import "sort"
+
+// IntestOnlySettings holds the linter settings as they appear in the
+// golangci-lint configuration. Options left unset keep the values of
+// DefaultConfig.
+type IntestOnlySettings struct {
+	EnableReflectionAnalysis          *bool    `mapstructure:"enable-reflection-analysis"`
+	ExampleFunctionsCountAsProduction *bool    `mapstructure:"example-functions-count-as-production"`
+	BenchmarksCountAsProduction       *bool    `mapstructure:"benchmarks-count-as-production"`
+	TreatTestdataAsTests              *bool    `mapstructure:"treat-testdata-as-tests"`
+	RespectBuildTags                  *bool    `mapstructure:"respect-build-tags"`
+	TreatLinkedFunctionsAsUsed        *bool    `mapstructure:"treat-linked-functions-as-used"`
+	SkipGeneratedFiles                *bool    `mapstructure:"skip-generated-files"`
+	OverrideIsTestFiles               []string `mapstructure:"override-is-test-files"`
+	OverrideIsProductionFiles         []string `mapstructure:"override-is-production-files"`
+	IgnoreFiles                       []string `mapstructure:"ignore-files"`
+	LibraryMode                       *bool    `mapstructure:"library-mode"`
+	SkipPackages                      []string `mapstructure:"skip-packages"`
+	IncludePatterns                   []string `mapstructure:"include-patterns"`
+	ExcludePatterns                   []string `mapstructure:"exclude-patterns"`
+	AlwaysUsed                        []string `mapstructure:"always-used"`
+	TestHelperPatterns                []string `mapstructure:"test-helper-patterns"`
+	ExplicitTestCases                 []string `mapstructure:"explicit-test-cases"`
+	ReportExplicitTestCases           *bool    `mapstructure:"report-explicit-test-cases"`
+	KnownImplicitMethods              []string `mapstructure:"known-implicit-methods"`
+	ConsiderReflectionRisky           *bool    `mapstructure:"consider-reflection-risky"`
+	ReflectionRiskPatterns            []string `mapstructure:"reflection-risk-patterns"`
+	AnnotateExclusions                *bool    `mapstructure:"annotate-exclusions"`
+	ReportUnusedEverywhere            *bool    `mapstructure:"report-unused-everywhere"`
+	CollapseFileLevelReports          *bool    `mapstructure:"collapse-file-level-reports"`
+	EnableStringLiteralAnalysis       *bool    `mapstructure:"enable-string-literal-analysis"`
+	StringLiteralTestUsages           *bool    `mapstructure:"string-literal-test-usages"`
+	EnableStructTagAnalysis           *bool    `mapstructure:"enable-struct-tag-analysis"`
+	EnableGoGenerateAnalysis          *bool    `mapstructure:"enable-go-generate-analysis"`
+	StringReferenceMinLength          *int     `mapstructure:"string-reference-min-length"`
+	VerifyAgainstTypesInfo            *bool    `mapstructure:"verify-against-types-info"`
+	CategoriesByVisibility            *bool    `mapstructure:"categories-by-visibility"`
+	MessageTemplate                   *string  `mapstructure:"message-template"`
+
+	// PackageOverrides holds settings for the packages matching each
+	// import path pattern, applied on top of the other settings
+	PackageOverrides map[string]IntestOnlySettings `mapstructure:"package-overrides"`
+}
+
+// ConvertSettings converts the linter settings into the analyzer
+// configuration. It doesn't validate the result; the analyzer does when it
+// runs, after applying the PackageOverrides of each package.
+func ConvertSettings(settings *IntestOnlySettings) *Config {
+	config := DefaultConfig()
+	if settings == nil {
+		return config
+	}
+
+	applySettings(config, settings)
+	config.PackageOverrides = settings.PackageOverrides
+
+	return config
+}
+
+// getConfig returns the configuration for the package with the given import
+// path, with the matching PackageOverrides applied in the order of their
+// patterns
+func getConfig(config *Config, pkgPath string) *Config {
+	if len(config.PackageOverrides) == 0 {
+		return config
+	}
+
+	patterns := make([]string, 0, len(config.PackageOverrides))
+	for pattern := range config.PackageOverrides {
+		if matchesPackage(pattern, pkgPath) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return config
+	}
+	sort.Strings(patterns)
+
+	merged := *config
+	for _, pattern := range patterns {
+		override := config.PackageOverrides[pattern]
+		applySettings(&merged, &override)
+	}
+
+	return &merged
+}
+
+// applySettings overrides the configuration with the settings that are set
+func applySettings(config *Config, settings *IntestOnlySettings) {
+	setBool(&config.EnableReflectionAnalysis, settings.EnableReflectionAnalysis)
+	setBool(&config.ExampleFunctionsCountAsProduction, settings.ExampleFunctionsCountAsProduction)
+	setBool(&config.BenchmarksCountAsProduction, settings.BenchmarksCountAsProduction)
+	setBool(&config.TreatTestdataAsTests, settings.TreatTestdataAsTests)
+	setBool(&config.RespectBuildTags, settings.RespectBuildTags)
+	setBool(&config.TreatLinkedFunctionsAsUsed, settings.TreatLinkedFunctionsAsUsed)
+	setBool(&config.SkipGeneratedFiles, settings.SkipGeneratedFiles)
+	setBool(&config.LibraryMode, settings.LibraryMode)
+	setBool(&config.ReportExplicitTestCases, settings.ReportExplicitTestCases)
+	setBool(&config.ConsiderReflectionRisky, settings.ConsiderReflectionRisky)
+	setBool(&config.AnnotateExclusions, settings.AnnotateExclusions)
+	setBool(&config.ReportUnusedEverywhere, settings.ReportUnusedEverywhere)
+	setBool(&config.CollapseFileLevelReports, settings.CollapseFileLevelReports)
+	setBool(&config.EnableStringLiteralAnalysis, settings.EnableStringLiteralAnalysis)
+	setBool(&config.StringLiteralTestUsages, settings.StringLiteralTestUsages)
+	setBool(&config.EnableStructTagAnalysis, settings.EnableStructTagAnalysis)
+	setBool(&config.EnableGoGenerateAnalysis, settings.EnableGoGenerateAnalysis)
+	setBool(&config.VerifyAgainstTypesInfo, settings.VerifyAgainstTypesInfo)
+	setBool(&config.CategoriesByVisibility, settings.CategoriesByVisibility)
+
+	if settings.MessageTemplate != nil {
+		config.MessageTemplate = *settings.MessageTemplate
+	}
+	if settings.StringReferenceMinLength != nil {
+		config.StringReferenceMinLength = *settings.StringReferenceMinLength
+	}
+	if settings.OverrideIsTestFiles != nil {
+		config.OverrideIsTestFiles = settings.OverrideIsTestFiles
+	}
+	if settings.OverrideIsProductionFiles != nil {
+		config.OverrideIsProductionFiles = settings.OverrideIsProductionFiles
+	}
+	if settings.IgnoreFiles != nil {
+		config.IgnoreFiles = settings.IgnoreFiles
+	}
+	if settings.SkipPackages != nil {
+		config.SkipPackages = settings.SkipPackages
+	}
+	if settings.IncludePatterns != nil {
+		config.IncludePatterns = settings.IncludePatterns
+	}
+	if settings.ExcludePatterns != nil {
+		config.ExcludePatterns = settings.ExcludePatterns
+	}
+	if settings.AlwaysUsed != nil {
+		config.AlwaysUsed = settings.AlwaysUsed
+	}
+	if settings.TestHelperPatterns != nil {
+		config.TestHelperPatterns = settings.TestHelperPatterns
+	}
+	if settings.ExplicitTestCases != nil {
+		config.ExplicitTestCases = settings.ExplicitTestCases
+	}
+	if settings.KnownImplicitMethods != nil {
+		config.KnownImplicitMethods = settings.KnownImplicitMethods
+	}
+	if settings.ReflectionRiskPatterns != nil {
+		config.ReflectionRiskPatterns = settings.ReflectionRiskPatterns
+	}
+}
+
+// setBool overrides dst with value when the setting is present
+func setBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
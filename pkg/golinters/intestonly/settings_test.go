@@ -0,0 +1,128 @@
+package intestonly
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertSettings(t *testing.T) {
+	if got := ConvertSettings(nil); !reflect.DeepEqual(got, DefaultConfig()) {
+		t.Errorf("ConvertSettings(nil) = %+v, want the default config", got)
+	}
+
+	if got := ConvertSettings(&IntestOnlySettings{}); !reflect.DeepEqual(got, DefaultConfig()) {
+		t.Errorf("Empty settings converted to %+v, want the default config", got)
+	}
+
+	disabled, enabled := false, true
+	got := ConvertSettings(&IntestOnlySettings{
+		EnableReflectionAnalysis:   &disabled,
+		LibraryMode:                &enabled,
+		OverrideIsTestFiles:        []string{"*_mock.go"},
+		SkipPackages:               []string{"example.com/mocks"},
+		ConsiderReflectionRisky:    &enabled,
+		ReflectionRiskPatterns:     []string{"Load*"},
+		IncludePatterns:            []string{"*Client"},
+		ExcludePatterns:            []string{"Legacy*"},
+		AlwaysUsed:                 []string{"PluginMain"},
+		TreatLinkedFunctionsAsUsed: &disabled,
+		SkipGeneratedFiles:         &disabled,
+		OverrideIsProductionFiles:  []string{"*_example.go"},
+		IgnoreFiles:                []string{"compat.go"},
+		TestHelperPatterns:         []string{"*Builder"},
+		ExplicitTestCases:          []string{"setupDatabase"},
+		ReportExplicitTestCases:    &enabled,
+	})
+	want := DefaultConfig()
+	want.EnableReflectionAnalysis = false
+	want.LibraryMode = true
+	want.OverrideIsTestFiles = []string{"*_mock.go"}
+	want.SkipPackages = []string{"example.com/mocks"}
+	want.ConsiderReflectionRisky = true
+	want.ReflectionRiskPatterns = []string{"Load*"}
+	want.IncludePatterns = []string{"*Client"}
+	want.ExcludePatterns = []string{"Legacy*"}
+	want.AlwaysUsed = []string{"PluginMain"}
+	want.TreatLinkedFunctionsAsUsed = false
+	want.SkipGeneratedFiles = false
+	want.OverrideIsProductionFiles = []string{"*_example.go"}
+	want.IgnoreFiles = []string{"compat.go"}
+	want.TestHelperPatterns = []string{"*Builder"}
+	want.ExplicitTestCases = []string{"setupDatabase"}
+	want.ReportExplicitTestCases = true
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ConvertSettings() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConvertSettingsStringLiteralAnalysis(t *testing.T) {
+	enabled, disabled := true, false
+
+	if ConvertSettings(nil).EnableStringLiteralAnalysis {
+		t.Error("String literal analysis should be disabled by default")
+	}
+	if !ConvertSettings(&IntestOnlySettings{EnableStringLiteralAnalysis: &enabled}).EnableStringLiteralAnalysis {
+		t.Error("enable-string-literal-analysis: true was not applied")
+	}
+	if ConvertSettings(&IntestOnlySettings{EnableStringLiteralAnalysis: &disabled}).EnableStringLiteralAnalysis {
+		t.Error("enable-string-literal-analysis: false was not applied")
+	}
+	if !ConvertSettings(&IntestOnlySettings{StringLiteralTestUsages: &enabled}).StringLiteralTestUsages {
+		t.Error("string-literal-test-usages: true was not applied")
+	}
+
+	if got := ConvertSettings(nil).StringReferenceMinLength; got != 3 {
+		t.Errorf("Expected a default minimum length of 3, got %d", got)
+	}
+	minLength := 8
+	if got := ConvertSettings(&IntestOnlySettings{StringReferenceMinLength: &minLength}).StringReferenceMinLength; got != 8 {
+		t.Errorf("string-reference-min-length: 8 was not applied, got %d", got)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	enabled, disabled := true, false
+	config := ConvertSettings(&IntestOnlySettings{
+		ConsiderReflectionRisky: &enabled,
+		PackageOverrides: map[string]IntestOnlySettings{
+			"example.com/app/api":       {LibraryMode: &enabled},
+			"example.com/app/*/mocks":   {EnableReflectionAnalysis: &disabled},
+			"example.com/app/api/inner": {ReflectionRiskPatterns: []string{"Load*"}},
+		},
+	})
+
+	t.Run("matching override", func(t *testing.T) {
+		got := getConfig(config, "example.com/app/api")
+		if !got.LibraryMode {
+			t.Error("Expected the override to enable library mode")
+		}
+		if !got.ConsiderReflectionRisky || !got.EnableReflectionAnalysis {
+			t.Error("Expected options the override leaves unset to keep their values")
+		}
+		if config.LibraryMode {
+			t.Error("Expected the base config to stay unchanged")
+		}
+	})
+
+	t.Run("nested overrides", func(t *testing.T) {
+		got := getConfig(config, "example.com/app/api/inner")
+		if !got.LibraryMode || len(got.ReflectionRiskPatterns) != 1 || got.ReflectionRiskPatterns[0] != "Load*" {
+			t.Errorf("Expected both matching overrides to apply, got %+v", got)
+		}
+	})
+
+	t.Run("non-matching path", func(t *testing.T) {
+		if got := getConfig(config, "example.com/app/apiclient"); got != config {
+			t.Errorf("Expected the base config, got %+v", got)
+		}
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		if getConfig(config, "example.com/app/users/mocks").EnableReflectionAnalysis {
+			t.Error("Expected the glob override to disable reflection analysis")
+		}
+		if !getConfig(config, "example.com/app/users/mocks/db").EnableReflectionAnalysis {
+			t.Error("Expected the glob override not to match a nested package")
+		}
+	})
+}
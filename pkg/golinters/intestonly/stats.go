@@ -0,0 +1,26 @@
+package intestonly
+
+// Stats counts the declarations of a package by what the analyzer did with
+// them. It is the result of the analyzer for every package; each checked
+// declaration is counted by exactly one of the other fields.
+type Stats struct {
+	Checked int // Declarations in production files
+
+	Exported        int // Exported declarations skipped in LibraryMode
+	Excluded        int // Names left out by the patterns or AlwaysUsed
+	TestHelpers     int // Names of test helpers
+	ImplicitMethods int // Methods called through standard interfaces
+	ReflectionRisky int // Names that reflection may resolve at run time
+	Ignored         int // Declarations suppressed by a comment directive
+	Linked          int // Functions implemented in assembly or linked by name
+	Generated       int // Declarations of generated files
+
+	UsedInProduction int // Declarations used in production code
+	Unused           int // Declarations that nothing uses
+	Reported         int // Declarations reported as only used in tests
+	Verified         int // Reports dropped by VerifyAgainstTypesInfo
+
+	// Declarations of the package under test that a black-box test package
+	// reports, which aren't among its own checked declarations
+	BlackBox int
+}
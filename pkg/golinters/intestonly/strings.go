@@ -0,0 +1,122 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// stringLiteralReferences returns the declared names that a string literal
+// mentions as calls, such as "handler(" in a template or a command line
+// help text
+func stringLiteralReferences(lit *ast.BasicLit, declared map[string][]types.Object, config *Config) []string {
+	if lit.Kind != token.STRING {
+		return nil
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+
+	return findFunctionReferencesInString(s, declared, config.StringReferenceMinLength)
+}
+
+// findFunctionReferencesInString returns the declared names of at least
+// minLength bytes that appear in s as whole identifiers directly followed by
+// an opening parenthesis, so "prefixSomeFunction()" doesn't mention
+// SomeFunction
+func findFunctionReferencesInString(s string, declared map[string][]types.Object, minLength int) []string {
+	var names []string
+	scanIdentifiers(s, func(name string, end int) {
+		if len(name) < minLength || end >= len(s) || s[end] != '(' {
+			return
+		}
+		if _, ok := declared[name]; ok {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// structTagReferences returns the declared names of at least minLength
+// bytes that appear as whole identifiers in the values of a struct tag, such
+// as MyValidator in `validate:"custom=MyValidator"`
+func structTagReferences(tag *ast.BasicLit, declared map[string][]types.Object, minLength int) []string {
+	text, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, value := range structTagValues(text) {
+		scanIdentifiers(value, func(name string, _ int) {
+			if _, ok := declared[name]; ok && len(name) >= minLength {
+				names = append(names, name)
+			}
+		})
+	}
+	return names
+}
+
+// structTagValues returns the values of a conventional struct tag made of
+// key:"value" pairs, like reflect.StructTag.Lookup parses them
+func structTagValues(tag string) []string {
+	var values []string
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		colon := strings.Index(tag, ":\"")
+		if colon <= 0 {
+			break
+		}
+		tag = tag[colon+1:]
+
+		// Find the closing quote, skipping escaped ones
+		i := 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+
+		if value, err := strconv.Unquote(tag[:i+1]); err == nil {
+			values = append(values, value)
+		}
+		tag = tag[i+1:]
+	}
+	return values
+}
+
+// scanIdentifiers calls fn for every identifier in s with the offset just
+// past its end
+func scanIdentifiers(s string, fn func(name string, end int)) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !isIdentRune(r) {
+			i += size
+			continue
+		}
+
+		start := i
+		for i < len(s) {
+			r, size = utf8.DecodeRuneInString(s[i:])
+			if !isIdentRune(r) {
+				break
+			}
+			i += size
+		}
+		fn(s[start:i], i)
+	}
+}
+
+// isIdentRune reports whether r can be part of a Go identifier
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,78 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFindFunctionReferencesInString(t *testing.T) {
+	declared := map[string][]types.Object{
+		"SomeFunction": nil,
+		"helper":       nil,
+		"load":         nil,
+		"id":           nil,
+	}
+
+	tests := map[string][]string{
+		"SomeFunction()":                  {"SomeFunction"},
+		"call SomeFunction(1) now":        {"SomeFunction"},
+		"pkg.SomeFunction()":              {"SomeFunction"},
+		"{{ helper(.Name) }}":             {"helper"},
+		"SomeFunction() and helper()":     {"SomeFunction", "helper"},
+		"prefixSomeFunction()":            nil,
+		"SomeFunctionSuffix()":            nil,
+		"SomeFunction is documented here": nil,
+		"SomeFunction ()":                 nil,
+		"unknown()":                       nil,
+		"load() then id()":                {"load"},
+	}
+
+	for s, want := range tests {
+		if got := findFunctionReferencesInString(s, declared, 3); !reflect.DeepEqual(got, want) {
+			t.Errorf("findFunctionReferencesInString(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestFindFunctionReferencesInStringMinLength(t *testing.T) {
+	declared := map[string][]types.Object{
+		"SomeFunction": nil,
+		"load":         nil,
+	}
+	s := "load() and SomeFunction()"
+
+	if got := findFunctionReferencesInString(s, declared, 3); !reflect.DeepEqual(got, []string{"load", "SomeFunction"}) {
+		t.Errorf("Expected both names to match, got %q", got)
+	}
+	if got := findFunctionReferencesInString(s, declared, 8); !reflect.DeepEqual(got, []string{"SomeFunction"}) {
+		t.Errorf("Expected only SomeFunction to match with a minimum length of 8, got %q", got)
+	}
+}
+
+func TestStructTagReferences(t *testing.T) {
+	declared := map[string][]types.Object{
+		"MyValidator": nil,
+		"required":    nil,
+		"id":          nil,
+	}
+
+	tests := map[string][]string{
+		`validate:"custom=MyValidator"`:             {"MyValidator"},
+		`json:"id" validate:"required,MyValidator"`: {"required", "MyValidator"},
+		`validate:"custom=MyValidatorV2"`:           nil,
+		`MyValidator:"x"`:                           nil,
+		`validate:"say \"MyValidator\""`:            {"MyValidator"},
+		`not a conventional tag MyValidator`:        nil,
+	}
+
+	for tag, want := range tests {
+		lit := &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(tag)}
+		if got := structTagReferences(lit, declared, 3); !reflect.DeepEqual(got, want) {
+			t.Errorf("structTagReferences(%q) = %q, want %q", tag, got, want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// typeReference is a declared type referenced in the declaration of another
+// type in production code, e.g. as an embedded type or the type of a field
+type typeReference struct {
+	obj   types.Object // Referenced type
+	pos   token.Pos    // Position of the reference
+	owner types.Object // Type whose declaration holds the reference
+}
+
+// typeReferences returns the identifiers of the declared types referenced in
+// the declarations of the declared types, keyed by position. A type is only
+// used by such a reference as much as the referring type is used itself,
+// so types that refer to each other don't keep each other alive.
+func typeReferences(pass *analysis.Pass, decls map[types.Object]intestOnlyInfo) map[token.Pos]types.Object {
+	owners := make(map[token.Pos]types.Object)
+	for owner, info := range decls {
+		spec, ok := info.node.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		ast.Inspect(spec.Type, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			obj, ok := pass.TypesInfo.Uses[ident].(*types.TypeName)
+			if !ok {
+				return true
+			}
+			if _, isDeclared := decls[obj]; isDeclared {
+				owners[ident.Pos()] = owner
+			}
+			return true
+		})
+	}
+	return owners
+}
+
+// propagateTypeReferences records the referenced types as used wherever
+// their referring types are used: in production when a referring type is
+// used in production, directly or through another reference, and in tests
+// when it is only used in tests. References in unused types are no usage
+// at all. Every reference is recorded at most once, which ends the
+// propagation on cycles of types referring to each other.
+func propagateTypeReferences(refs []typeReference, nonTestUsages, testUsages map[types.Object]bool, isProduction func(owner types.Object) bool, record func(r typeReference, isTest bool)) {
+	resolved := make([]bool, len(refs))
+	for changed := true; changed; {
+		changed = false
+		for i, r := range refs {
+			if resolved[i] || !(nonTestUsages[r.owner] || isProduction(r.owner)) {
+				continue
+			}
+			record(r, false)
+			resolved[i] = true
+			changed = true
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i, r := range refs {
+			if resolved[i] || !testUsages[r.owner] {
+				continue
+			}
+			record(r, true)
+			resolved[i] = true
+			changed = true
+		}
+	}
+}
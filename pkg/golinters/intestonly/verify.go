@@ -0,0 +1,42 @@
+package intestonly
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// verifyTestOnly drops the test-only declarations that the type checker
+// sees used in a production file outside their own declaration, which the
+// usage heuristics missed or discounted on purpose, and logs each of them
+func verifyTestOnly(pass *analysis.Pass, testOnly []intestOnlyInfo, testFiles *testFileCache, log Logger) []intestOnlyInfo {
+	uses := make(map[types.Object][]*ast.Ident)
+	for ident, obj := range pass.TypesInfo.Uses {
+		uses[obj] = append(uses[obj], ident)
+	}
+
+	var verified []intestOnlyInfo
+	for _, info := range testOnly {
+		if use := productionUse(pass, info, uses, testFiles); use != nil {
+			log.Debugf("Not reporting %s: the type checker sees a production use at %s", info.name, pass.Fset.Position(use.Pos()))
+			continue
+		}
+		verified = append(verified, info)
+	}
+	return verified
+}
+
+// productionUse returns a use of the declared object in a production file
+// outside its own declaration, or nil if there is none
+func productionUse(pass *analysis.Pass, info intestOnlyInfo, uses map[types.Object][]*ast.Ident, testFiles *testFileCache) *ast.Ident {
+	for _, use := range uses[info.obj] {
+		if use.Pos() >= info.node.Pos() && use.Pos() < info.node.End() {
+			continue
+		}
+		if !testFiles.isTestFile(pass.Fset.File(use.Pos()).Name()) {
+			return use
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package assignments
+
+import "strings"
+
+// Test case for a type only named in a tuple return
+type pair struct {
+	key, value string
+}
+
+// Test case for a function only called in a multi-value assignment
+func split(s string) (pair, bool) {
+	key, value, ok := strings.Cut(s, "=")
+	return pair{key: key, value: value}, ok
+}
+
+// Test case for a function only called with blank identifiers on the left
+func parse(s string) (int, error) {
+	return len(s), nil
+}
+
+// Test case for a function only called in a multi-value assignment in tests
+func swap(a, b string) (string, string) { // want "identifier \"swap\" is only used in test files but is not part of test files"
+	return b, a
+}
+
+// Key returns the key of a key=value setting, checking it parses
+func Key(s string) string {
+	_, _ = parse(s)
+	p, _ := split(s)
+	return p.key
+}
+
+var key = Key("a=b")
@@ -0,0 +1,16 @@
+package assignments
+
+import "testing"
+
+func TestAssignments(t *testing.T) {
+	a, b := swap("a", "b")
+	if a != "b" || b != "a" {
+		t.Error("unexpected swap")
+	}
+	if p, ok := split("k=v"); !ok || p.value != "v" {
+		t.Error("unexpected split")
+	}
+	if n, err := parse("abc"); err != nil || n != 3 {
+		t.Error("unexpected parse")
+	}
+}
@@ -0,0 +1,11 @@
+package benchmarks
+
+// Test case for a function only called in a benchmark
+func encode(data []byte) string { // want "identifier \"encode\" is only used in test files but is not part of test files"
+	return string(data)
+}
+
+// Test case for a function only called in a regular test
+func validate(data []byte) bool { // want "identifier \"validate\" is only used in test files but is not part of test files"
+	return len(data) > 0
+}
@@ -0,0 +1,15 @@
+package benchmarks
+
+import "testing"
+
+func BenchmarkEncode(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = encode([]byte("data"))
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if !validate([]byte("data")) {
+		t.Error("unexpected validation result")
+	}
+}
@@ -0,0 +1,51 @@
+package blackbox
+
+// Test case for a function only used by the black-box test package
+func Exported() string { // want "identifier \"Exported\" is only used in test files but is not part of test files"
+	return "exported"
+}
+
+// Test case for a function used by both kinds of tests
+func internal() string {
+	return "internal"
+}
+
+// Test case for a method only called by the black-box test package
+type Client struct{}
+
+func (c *Client) Fetch() string { // want "identifier \"Fetch\" is only used in test files but is not part of test files"
+	return "fetched"
+}
+
+// NewClient creates a client
+func NewClient() *Client {
+	return &Client{}
+}
+
+var defaultClient = NewClient()
+
+// Internal exposes internal to production code
+func Internal() string {
+	return internal()
+}
+
+// Test case for a method sharing its name with testing.T.Run, which the
+// black-box test calls
+type Job struct{}
+
+func (j *Job) Run() error {
+	return nil
+}
+
+var defaultJob = &Job{}
+
+// Test case for a function only called through a dot import
+func Dotted() string { // want "identifier \"Dotted\" is only used in test files but is not part of test files"
+	return "dotted"
+}
+
+// Test case for a function sharing its name with a local variable of a test
+// file that dot imports the package
+func Shadowed() string {
+	return "shadowed"
+}
@@ -0,0 +1,16 @@
+package blackbox_test
+
+import (
+	"testing"
+
+	. "blackbox"
+)
+
+func TestDotted(t *testing.T) {
+	t.Run("dotted", func(t *testing.T) {
+		Shadowed := Dotted()
+		if Shadowed != "dotted" {
+			t.Error("unexpected dotted result")
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package blackbox_test
+
+import (
+	"testing"
+
+	"blackbox"
+)
+
+func TestExported(t *testing.T) {
+	if blackbox.Exported() != "exported" {
+		t.Error("unexpected exported result")
+	}
+	if blackbox.NewClient().Fetch() != "fetched" {
+		t.Error("unexpected fetch result")
+	}
+}
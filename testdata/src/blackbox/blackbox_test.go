@@ -0,0 +1,9 @@
+package blackbox
+
+import "testing"
+
+func TestInternal(t *testing.T) {
+	if internal() != "internal" {
+		t.Error("unexpected internal result")
+	}
+}
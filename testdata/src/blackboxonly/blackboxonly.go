@@ -0,0 +1,13 @@
+package blackboxonly
+
+// Test case for a package that only has black-box tests
+func Exported() string { // want "identifier \"Exported\" is only used in test files but is not part of test files"
+	return "exported"
+}
+
+// Test case for a function used in production
+func Used() string {
+	return "used"
+}
+
+var used = Used()
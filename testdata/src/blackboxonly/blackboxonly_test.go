@@ -0,0 +1,13 @@
+package blackboxonly_test
+
+import (
+	"testing"
+
+	bb "blackboxonly"
+)
+
+func TestExported(t *testing.T) {
+	if bb.Exported() != "exported" || bb.Used() != "used" {
+		t.Error("unexpected result")
+	}
+}
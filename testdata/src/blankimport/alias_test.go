@@ -0,0 +1,15 @@
+package blankimport_test
+
+import (
+	"testing"
+
+	bi "blankimport"
+)
+
+import _ "blankimport"
+
+func TestAliased(t *testing.T) {
+	if bi.Aliased() != "aliased" {
+		t.Error("unexpected result")
+	}
+}
@@ -0,0 +1,17 @@
+package blankimport_test
+
+import (
+	"testing"
+
+	_ "blankimport"
+)
+
+func Unused() string {
+	return "local"
+}
+
+func TestBlank(t *testing.T) {
+	if Unused() != "local" {
+		t.Error("unexpected result")
+	}
+}
@@ -0,0 +1,13 @@
+package blankimport
+
+// Test case for a function called through an aliased import in a black-box
+// test that also imports the package blank
+func Aliased() string { // want "identifier \"Aliased\" is only used in test files but is not part of test files"
+	return "aliased"
+}
+
+// Test case for a function sharing its name with a declaration of a test
+// file that only imports the package blank
+func Unused() string {
+	return "unused"
+}
@@ -0,0 +1,6 @@
+package buildtags
+
+// Name returns the name of the package
+func Name() string {
+	return "buildtags"
+}
@@ -0,0 +1,9 @@
+//go:build intestonly_extra
+
+package buildtags
+
+// Test case for a function of a file built only with the intestonly_extra
+// tag, used by a test with the same tag
+func extraOnly() string {
+	return "extra"
+}
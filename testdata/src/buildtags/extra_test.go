@@ -0,0 +1,11 @@
+//go:build intestonly_extra
+
+package buildtags
+
+import "testing"
+
+func TestExtra(t *testing.T) {
+	if extraOnly() != "extra" {
+		t.Error("unexpected result")
+	}
+}
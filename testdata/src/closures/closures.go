@@ -0,0 +1,30 @@
+package closures
+
+import "strings"
+
+// Test case for a function only called from a returned closure
+func shout(s string) string {
+	return strings.ToUpper(s) + "!"
+}
+
+// Test case for a function only called from a nested closure
+func quote(s string) string {
+	return "'" + s + "'"
+}
+
+// Formatter returns a function that formats messages
+func Formatter() func(string) string {
+	return func(s string) string {
+		wrap := func(s string) string {
+			return quote(s)
+		}
+		return wrap(shout(s))
+	}
+}
+
+var format = Formatter()
+
+// Test case for a function only called from a closure in a test
+func whisper(s string) string { // want "identifier \"whisper\" is only used in test files but is not part of test files"
+	return strings.ToLower(s)
+}
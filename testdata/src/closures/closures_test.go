@@ -0,0 +1,15 @@
+package closures
+
+import "testing"
+
+func TestFormatter(t *testing.T) {
+	check := func(got, want string) {
+		if got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+	check(Formatter()("hi"), "'HI!'")
+	check(shout("a"), "A!")
+	check(quote("a"), "'a'")
+	check(func() string { return whisper("A") }(), "a")
+}
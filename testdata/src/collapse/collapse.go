@@ -0,0 +1,11 @@
+package collapse
+
+// Count returns the number of items
+func Count(items []string) int {
+	return len(items)
+}
+
+// Test case for a test-only function next to production code
+func debugDump(items []string) string { // want "identifier \"debugDump\" is only used in test files but is not part of test files"
+	return items[0]
+}
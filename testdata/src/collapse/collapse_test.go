@@ -0,0 +1,13 @@
+package collapse
+
+import "testing"
+
+func TestSamples(t *testing.T) {
+	samples := make([]sample, sampleCount)
+	if sampleName(samples[0].id) != "sample" {
+		t.Error("unexpected sample name")
+	}
+	if debugDump([]string{"a"}) != "a" {
+		t.Error("unexpected dump")
+	}
+}
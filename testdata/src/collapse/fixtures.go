@@ -0,0 +1,12 @@
+package collapse
+
+// Test case for a file whose declarations are all only used in tests
+type sample struct { // want "identifier \"sample\" is only used in test files but is not part of test files"
+	id int
+}
+
+const sampleCount = 3 // want "identifier \"sampleCount\" is only used in test files but is not part of test files"
+
+func sampleName(id int) string { // want "identifier \"sampleName\" is only used in test files but is not part of test files"
+	return "sample"
+}
@@ -0,0 +1,44 @@
+package composite
+
+// Test case for a type only used as a slice element in production code
+type step struct {
+	name string
+}
+
+// Test case for a type only used as a map value in production code
+type route struct {
+	path string
+}
+
+// Test case for a type only used as an array element in production code
+type slot int
+
+// Test case for a type only used as a slice element in tests
+type fixture struct { // want "identifier \"fixture\" is only used in test files but is not part of test files"
+	input string
+}
+
+var pipeline = []step{{name: "build"}, {name: "test"}}
+
+var routes = map[string]route{"home": {path: "/"}}
+
+var slots = [2]slot{1, 2}
+
+// Steps returns the names of the pipeline steps
+func Steps() []string {
+	names := make([]string, 0, len(pipeline))
+	for _, s := range pipeline {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// Route returns the path of a named route
+func Route(name string) string {
+	return routes[name].path
+}
+
+// Slots returns the number of slots
+func Slots() int {
+	return len(slots)
+}
@@ -0,0 +1,14 @@
+package composite
+
+import "testing"
+
+func TestComposite(t *testing.T) {
+	steps := []step{{name: "build"}}
+	routes := map[string]route{"home": {path: "/"}}
+	slots := [1]slot{1}
+	fixtures := []fixture{{input: "a"}}
+
+	if len(steps) != 1 || len(routes) != 1 || len(slots) != 1 || len(fixtures) != 1 {
+		t.Error("unexpected literals")
+	}
+}
@@ -0,0 +1,31 @@
+package constexpr
+
+// Test case for a constant only used as an array size
+const bufSize = 16
+
+// Test case for a constant only used in another constant expression
+const shift = 2
+
+// Test case for a constant only used as a case label
+const modeFast = 1
+
+// Test case for a constant only used in tests
+const maxRetries = 3 // want "identifier \"maxRetries\" is only used in test files but is not part of test files"
+
+// Mask selects the low bits
+const Mask = 1<<shift - 1
+
+// Buffer holds a fixed amount of data
+type Buffer struct {
+	data [bufSize]byte
+}
+
+// Speed describes a mode
+func Speed(mode int) string {
+	switch mode {
+	case modeFast:
+		return "fast"
+	default:
+		return "slow"
+	}
+}
@@ -0,0 +1,9 @@
+package constexpr
+
+import "testing"
+
+func TestConstants(t *testing.T) {
+	if bufSize != 16 || shift != 2 || modeFast != 1 || maxRetries != 3 {
+		t.Error("unexpected constants")
+	}
+}
@@ -0,0 +1,43 @@
+package controlflow
+
+// Test case for a function only called in a select case
+func handle(v int) int {
+	return v * 2
+}
+
+// Test case for a function only called in a labeled loop
+func valid(v int) bool {
+	return v >= 0
+}
+
+// Test case for a function only called in a select case in tests
+func drain(ch chan int) { // want "identifier \"drain\" is only used in test files but is not part of test files"
+	for range ch {
+	}
+}
+
+// Sum doubles and adds up the valid values received before done is closed
+func Sum(values <-chan int, done <-chan struct{}) int {
+	total := 0
+loop:
+	for {
+		select {
+		case v := <-values:
+			if !valid(v) {
+				continue loop
+			}
+			total += handle(v)
+		case <-done:
+			break loop
+		}
+	}
+	return total
+}
+
+var sum = Sum(nil, closed())
+
+func closed() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}
@@ -0,0 +1,16 @@
+package controlflow
+
+import "testing"
+
+func TestSelect(t *testing.T) {
+	ch := make(chan int)
+	close(ch)
+	select {
+	case <-ch:
+		drain(ch)
+	default:
+	}
+	if handle(1) != 2 || !valid(1) {
+		t.Error("unexpected result")
+	}
+}
@@ -0,0 +1,37 @@
+package conversions
+
+import "strings"
+
+// Test case for a type only used as a conversion target in production
+type label string
+
+// Normalize lowercases a label
+func Normalize(s string) string {
+	return strings.ToLower(string(label(s)))
+}
+
+var normalized = Normalize("Default")
+
+// Test case for a type only converted to in tests
+type code int // want "identifier \"code\" is only used in test files but is not part of test files"
+
+// Test case for a type only allocated with the builtin new in production
+type counter struct {
+	n int
+}
+
+// Count counts the values with a freshly allocated counter
+func Count(values []string) int {
+	c := new(counter)
+	for range values {
+		c.n++
+	}
+	return c.n
+}
+
+var counted = Count(nil)
+
+// Test case for a type only allocated with the builtin new in tests
+type buffer struct { // want "identifier \"buffer\" is only used in test files but is not part of test files"
+	data []byte
+}
@@ -0,0 +1,15 @@
+package conversions
+
+import "testing"
+
+func TestConversions(t *testing.T) {
+	if label("x") != "x" || code(1) != 1 {
+		t.Error("unexpected conversion")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if b := new(buffer); len(b.data) != 0 || new(counter).n != 0 {
+		t.Error("unexpected allocation")
+	}
+}
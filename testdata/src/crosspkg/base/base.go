@@ -0,0 +1,21 @@
+package base
+
+// Base is embedded by types from other packages
+type Base struct {
+	ID string
+}
+
+// Format formats an identifier for display
+func Format(id string) string {
+	return "#" + id
+}
+
+// New creates a base with the given identifier
+func New(id string) *Base {
+	return &Base{ID: id}
+}
+
+// Describe formats the base for display
+func (b Base) Describe() string {
+	return Format(b.ID)
+}
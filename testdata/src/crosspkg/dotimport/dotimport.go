@@ -0,0 +1,23 @@
+package dotimport
+
+import . "crosspkg/base"
+
+// Label formats the identifier of a dot-imported type
+func Label(b Base) string {
+	return Format(b.ID)
+}
+
+type record struct {
+	id string
+}
+
+// Test case for a method sharing its name with a dot-imported function that
+// production code calls
+func (r record) Format() string { // want "identifier \"Format\" is only used in test files but is not part of test files"
+	return r.id
+}
+
+// Test case for a function only used in tests next to dot-imported names
+func validID(id string) bool { // want "identifier \"validID\" is only used in test files but is not part of test files"
+	return id != ""
+}
@@ -0,0 +1,10 @@
+package dotimport
+
+import "testing"
+
+func TestRecord(t *testing.T) {
+	r := record{id: "a"}
+	if r.Format() != "a" || !validID(r.id) {
+		t.Error("unexpected record")
+	}
+}
@@ -0,0 +1,20 @@
+package embedding
+
+import "crosspkg/base"
+
+// Test case for a production struct embedding a type from another package
+type Server struct {
+	base.Base
+}
+
+// NewServer creates a server
+func NewServer(id string) *Server {
+	return &Server{Base: base.Base{ID: id}}
+}
+
+var defaultServer = NewServer("default")
+
+// Test case for a local type sharing its name with the embedded one
+type Base struct { // want "identifier \"Base\" is only used in test files but is not part of test files"
+	Name string
+}
@@ -0,0 +1,14 @@
+package embedding
+
+import "testing"
+
+func TestEmbedding(t *testing.T) {
+	if NewServer("id").ID != "id" {
+		t.Error("unexpected server id")
+	}
+
+	b := Base{Name: "local"}
+	if b.Name != "local" {
+		t.Error("unexpected base name")
+	}
+}
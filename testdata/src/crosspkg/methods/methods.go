@@ -0,0 +1,20 @@
+package methods
+
+import "crosspkg/base"
+
+// Summary describes bases through method calls on imported types
+func Summary() string {
+	return base.Base{ID: "a"}.Describe() + base.New("b").Describe()
+}
+
+var summary = Summary()
+
+type entry struct {
+	name string
+}
+
+// Test case for a method sharing its name with a method of an imported type
+// that production code calls
+func (e entry) Describe() string { // want "identifier \"Describe\" is only used in test files but is not part of test files"
+	return e.name
+}
@@ -0,0 +1,9 @@
+package methods
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	if (entry{name: "a"}).Describe() != "a" {
+		t.Error("unexpected description")
+	}
+}
@@ -0,0 +1,38 @@
+package deferred
+
+import "sync"
+
+// Test case for a function only called in a defer statement
+func release(mu *sync.Mutex) {
+	mu.Unlock()
+}
+
+// Pool runs jobs in the background
+type Pool struct {
+	wg sync.WaitGroup
+}
+
+// Test case for a method only launched in a go statement
+func (p *Pool) work(job func()) {
+	defer p.wg.Done()
+	job()
+}
+
+// Test case for a function only deferred in tests
+func restore(state *int) { // want "identifier \"restore\" is only used in test files but is not part of test files"
+	*state = 0
+}
+
+// Run runs the job in the background and waits for it
+func (p *Pool) Run(job func()) {
+	p.wg.Add(1)
+	go p.work(job)
+	p.wg.Wait()
+}
+
+// Locked calls fn while holding mu
+func Locked(mu *sync.Mutex, fn func()) {
+	mu.Lock()
+	defer release(mu)
+	fn()
+}
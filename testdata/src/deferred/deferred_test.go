@@ -0,0 +1,19 @@
+package deferred
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestHelpers(t *testing.T) {
+	var mu sync.Mutex
+	mu.Lock()
+	release(&mu)
+
+	p := &Pool{}
+	p.wg.Add(1)
+	p.work(func() {})
+
+	state := 1
+	defer restore(&state)
+}
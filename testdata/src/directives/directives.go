@@ -0,0 +1,40 @@
+package directives
+
+// Test case for a function suppressed with the dedicated directive
+//
+//intestonly:ignore
+func ignoredFunction() string {
+	return "ignored"
+}
+
+// Test case for a type suppressed with a nolint directive
+//
+//nolint:intestonly
+type NolintType struct {
+	Field string
+}
+
+// Test case for same-line directives
+func trailingNolint() string { //nolint:unused,intestonly // kept for plugins
+	return "trailing"
+}
+
+type TrailingIgnoreType struct{} //intestonly:ignore
+
+// Test case for directives of a declaration group
+//
+// nolint
+const (
+	firstGrouped  = "first"
+	secondGrouped = "second"
+)
+
+// Test case for a nolint directive for other linters
+func otherNolint() string { //nolint:unused // want "identifier \"otherNolint\" is only used in test files but is not part of test files"
+	return "other"
+}
+
+// Test case for a declaration without directives
+func reportedFunction() string { // want "identifier \"reportedFunction\" is only used in test files but is not part of test files"
+	return "reported"
+}
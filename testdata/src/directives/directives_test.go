@@ -0,0 +1,14 @@
+package directives
+
+import "testing"
+
+func TestDirectives(t *testing.T) {
+	_ = ignoredFunction()
+	_ = NolintType{Field: "test"}
+	_ = trailingNolint()
+	_ = TrailingIgnoreType{}
+	_ = firstGrouped + secondGrouped
+	_ = otherNolint()
+	_ = reportedFunction()
+	_ = ignoredFile()
+}
@@ -0,0 +1,9 @@
+// Test case for a file whose declarations are all suppressed
+//
+//intestonly:file-ignore
+
+package directives
+
+func ignoredFile() string {
+	return "ignored"
+}
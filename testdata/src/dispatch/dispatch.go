@@ -0,0 +1,34 @@
+package dispatch
+
+type router struct {
+	handler func(string) string
+}
+
+// Test case for a function only placed into a struct field
+func handleLogin(user string) string {
+	return "login " + user
+}
+
+// Test case for a function only placed into a map of handlers
+func handleLogout(user string) string {
+	return "logout " + user
+}
+
+// Test case for a function only placed into a struct field in tests
+func handleDebug(user string) string { // want "identifier \"handleDebug\" is only used in test files but is not part of test files"
+	return "debug " + user
+}
+
+var login = router{handler: handleLogin}
+
+var handlers = map[string]func(string) string{
+	"logout": handleLogout,
+}
+
+// Handle dispatches an action
+func Handle(action, user string) string {
+	if action == "login" {
+		return login.handler(user)
+	}
+	return handlers[action](user)
+}
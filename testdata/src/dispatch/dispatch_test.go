@@ -0,0 +1,10 @@
+package dispatch
+
+import "testing"
+
+func TestHandlers(t *testing.T) {
+	r := router{handler: handleDebug}
+	if r.handler("a") != "debug a" || handleLogin("a") != "login a" || handleLogout("a") != "logout a" {
+		t.Error("unexpected handler result")
+	}
+}
@@ -0,0 +1,87 @@
+package embedded
+
+// Test case for a type embedded in a struct that only tests use
+type BaseType struct { // want "identifier \"BaseType\" is only used in test files but is not part of test files"
+	ID string
+}
+
+// Test case for a production struct that is only used in tests
+type TestOnlyWrapper struct { // want "identifier \"TestOnlyWrapper\" is only used in test files but is not part of test files"
+	BaseType
+	Label string
+}
+
+// Test case for a type embedded through a pointer in a struct used in production
+type Logger struct {
+	Prefix string
+}
+
+// Service is used in production
+type Service struct {
+	*Logger
+	Name string
+}
+
+// Test case for a chain of embeddings ending in a struct used in production
+type Inner struct {
+	Value int
+}
+
+// Middle embeds Inner
+type Middle struct {
+	Inner
+}
+
+// Outer embeds Middle and is used in production
+type Outer struct {
+	Middle
+}
+
+// Test case for a type embedded in a struct that nothing uses
+type Orphan struct { // want "identifier \"Orphan\" is only used in test files but is not part of test files"
+	Count int
+}
+
+// Unused embeds Orphan
+type Unused struct {
+	Orphan
+}
+
+// NewService creates a service
+func NewService(name string) *Service {
+	return &Service{Name: name}
+}
+
+// Size reports the size of an outer value
+func Size(o Outer) int {
+	return o.Value
+}
+
+var (
+	defaultService = NewService("default")
+	defaultSize    = Size(Outer{})
+)
+
+// Test case for a type only embedded in a struct of a test file
+type Clock struct { // want "identifier \"Clock\" is only used in test files but is not part of test files"
+	Now int64
+}
+
+// Test case for an interface embedded in an interface used in production
+type Notifier interface {
+	Notify(message string)
+}
+
+// Publisher embeds Notifier and is used in production
+type Publisher interface {
+	Notifier
+	Close() error
+}
+
+// Publish sends a message through the publisher and closes it
+func Publish(p Publisher, message string) error {
+	p.Notify(message)
+	return p.Close()
+}
+
+var publish = Publish
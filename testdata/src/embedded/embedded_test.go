@@ -0,0 +1,31 @@
+package embedded
+
+import "testing"
+
+func TestWrapper(t *testing.T) {
+	w := TestOnlyWrapper{Label: "x"}
+	w.ID = "id"
+	if w.ID != "id" {
+		t.Fatal("unexpected id")
+	}
+}
+
+func TestService(t *testing.T) {
+	s := NewService("svc")
+	s.Logger = &Logger{Prefix: "svc: "}
+	if Size(Outer{Middle{Inner{Value: 1}}}) != 1 {
+		t.Fatal("unexpected size")
+	}
+	_ = Orphan{}
+}
+
+type fakeClock struct {
+	Clock
+}
+
+func TestClock(t *testing.T) {
+	c := fakeClock{Clock{Now: 1}}
+	if c.Now != 1 {
+		t.Fatal("unexpected time")
+	}
+}
@@ -0,0 +1,16 @@
+package examples
+
+// Test case for a function only used in an example
+func Greet(name string) string { // want "identifier \"Greet\" is only used in test files but is not part of test files"
+	return "Hello, " + name
+}
+
+// Test case for a function only used in a regular test
+func farewell(name string) string { // want "identifier \"farewell\" is only used in test files but is not part of test files"
+	return "Bye, " + name
+}
+
+// Test case for a function only used in a benchmark
+func repeat(name string) string { // want "identifier \"repeat\" is only used in test files but is not part of test files"
+	return name + name
+}
@@ -0,0 +1,28 @@
+package examples
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleGreet() {
+	fmt.Println(Greet("gopher"))
+	// Output: Hello, gopher
+}
+
+func TestFarewell(t *testing.T) {
+	if farewell("gopher") != "Bye, gopher" {
+		t.Error("unexpected farewell")
+	}
+}
+
+func BenchmarkRepeat(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = repeat("gopher")
+	}
+}
+
+// Examplesque is a helper that is not an example despite its prefix
+func Examplesque() string {
+	return farewell("helper")
+}
@@ -0,0 +1,13 @@
+package explicit
+
+// Test case for a function named like a test helper that only tests use
+func setupDatabase() string {
+	return "db"
+}
+
+// Test case for a function named like a test helper that production uses
+func mockClock() string {
+	return "clock"
+}
+
+var clock = mockClock()
@@ -0,0 +1,9 @@
+package explicit
+
+import "testing"
+
+func TestExplicit(t *testing.T) {
+	if setupDatabase() == "" || mockClock() == "" {
+		t.Error("unexpected empty result")
+	}
+}
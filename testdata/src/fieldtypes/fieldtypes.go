@@ -0,0 +1,20 @@
+package fieldtypes
+
+// Test case for the type of a field of a type that is always used
+type Plugin struct {
+	cfg PluginConfig
+}
+
+// PluginConfig configures a plugin
+type PluginConfig struct {
+	Name string
+}
+
+// Test case for the type of a field of a reflection-risky type
+type RequestHandler struct {
+	opts handlerOptions
+}
+
+type handlerOptions struct {
+	verbose bool
+}
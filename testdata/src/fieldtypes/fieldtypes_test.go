@@ -0,0 +1,11 @@
+package fieldtypes
+
+import "testing"
+
+func TestFieldTypes(t *testing.T) {
+	p := Plugin{cfg: PluginConfig{Name: "x"}}
+	h := RequestHandler{opts: handlerOptions{verbose: true}}
+	if p.cfg.Name != "x" || !h.opts.verbose {
+		t.Error("unexpected field values")
+	}
+}
@@ -0,0 +1,37 @@
+package fixes
+
+// Test case for removing a function together with its doc comment
+func unusedFunction() string { // want "identifier \"unusedFunction\" is only used in test files but is not part of test files"
+	return "test"
+}
+
+// Test case for removing a single-spec type declaration
+type UnusedType struct { // want "identifier \"UnusedType\" is only used in test files but is not part of test files"
+	Field string
+}
+
+// Test case for removing one constant from a group
+const (
+	keptConstant   = "kept"
+	unusedConstant = "unused" // want "identifier \"unusedConstant\" is only used in test files but is not part of test files"
+)
+
+// Test case for constants that can't be removed without shifting iota
+const (
+	firstLevel  = iota
+	unusedLevel // want "identifier \"unusedLevel\" is only used in test files but is not part of test files"
+	lastLevel
+)
+
+// Test case for a spec declaring several names
+var keptVariable, unusedVariable = "kept", "unused" // want "identifier \"unusedVariable\" is only used in test files but is not part of test files"
+
+// Levels returns the levels used in production
+func Levels() []int {
+	return []int{firstLevel, lastLevel}
+}
+
+// Kept returns the values used in production
+func Kept() string {
+	return keptConstant + keptVariable
+}
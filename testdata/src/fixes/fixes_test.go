@@ -0,0 +1,14 @@
+package fixes
+
+import "testing"
+
+func TestFixes(t *testing.T) {
+	if unusedFunction() != "test" {
+		t.Error("unexpected function result")
+	}
+
+	u := UnusedType{Field: unusedConstant}
+	if u.Field != "unused" || unusedVariable != "unused" || unusedLevel != 1 {
+		t.Error("unexpected values")
+	}
+}
@@ -0,0 +1,18 @@
+package generate
+
+//go:generate mockgen -destination=mock_store.go -package=generate . Store,Clock
+
+// Test case for an interface only referenced by a go:generate directive
+type Store interface { // want "identifier \"Store\" is only used in test files but is not part of test files"
+	Get(key string) (string, bool)
+}
+
+// Test case for an interface in a comma-separated list of a directive
+type Clock interface { // want "identifier \"Clock\" is only used in test files but is not part of test files"
+	Now() int64
+}
+
+// Test case for an interface only referenced by a directive in a test file
+type Cache interface { // want "identifier \"Cache\" is only used in test files but is not part of test files"
+	Put(key, value string)
+}
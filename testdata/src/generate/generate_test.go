@@ -0,0 +1,14 @@
+package generate
+
+import "testing"
+
+//go:generate mockgen -destination=mock_cache_test.go -package=generate . Cache
+
+func TestInterfaces(t *testing.T) {
+	var s Store
+	var c Clock
+	var ca Cache
+	if s != nil || c != nil || ca != nil {
+		t.Error("unexpected implementation")
+	}
+}
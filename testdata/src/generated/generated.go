@@ -0,0 +1,11 @@
+package generated
+
+// Test case for a function only used by generated code
+func encode(s string) []byte {
+	return []byte(s)
+}
+
+// Test case for a hand-written function only used in tests
+func decode(b []byte) string { // want "identifier \"decode\" is only used in test files but is not part of test files"
+	return string(b)
+}
@@ -0,0 +1,10 @@
+package generated
+
+import "testing"
+
+func TestRequest(t *testing.T) {
+	r := &Request{ID: "x"}
+	if r.GetID() != "x" || decode(r.Marshal()) != "x" {
+		t.Error("unexpected request")
+	}
+}
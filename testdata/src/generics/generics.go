@@ -0,0 +1,43 @@
+package generics
+
+// Test case for a generic function used in production code
+func Map[In, Out any](values []In, fn func(In) Out) []Out {
+	result := make([]Out, 0, len(values))
+	for _, v := range values {
+		result = append(result, fn(v))
+	}
+	return result
+}
+
+// Lengths returns the lengths of the given strings
+func Lengths(values []string) []int {
+	return Map[string, int](values, func(s string) int { return len(s) })
+}
+
+// Test case for a generic type only used in tests
+type Stack[Elem any] struct { // want "identifier \"Stack\" is only used in test files but is not part of test files"
+	items []Elem
+}
+
+// Test case for a type sharing its name with a type parameter
+type Elem struct { // want "identifier \"Elem\" is only used in test files but is not part of test files"
+	Value string
+}
+
+// Test case for a generic function only called, with inferred type
+// arguments, by another generic function
+func filter[T any](values []T, keep func(T) bool) []T {
+	var result []T
+	for _, v := range values {
+		if keep(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Test case for a generic function only used in tests that calls another
+func Compact[T comparable](values []T) []T { // want "identifier \"Compact\" is only used in test files but is not part of test files"
+	var zero T
+	return filter(values, func(v T) bool { return v != zero })
+}
@@ -0,0 +1,22 @@
+package generics
+
+import "testing"
+
+func TestGenerics(t *testing.T) {
+	// Test generic function instantiation
+	if got := Map([]int{1, 2}, func(i int) int { return i * 2 }); got[1] != 4 {
+		t.Errorf("unexpected map result %v", got)
+	}
+
+	// Test generic type instantiation
+	s := Stack[Elem]{items: []Elem{{Value: "test"}}}
+	if s.items[0].Value != "test" {
+		t.Error("unexpected stack item")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	if got := Compact([]string{"a", "", "b"}); len(got) != 2 {
+		t.Errorf("unexpected compact result %v", got)
+	}
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlers(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rootHandler{}.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if n, _ := (discard{}).Write([]byte("x")); n != 1 || (discard{}).size() != 0 {
+		t.Error("unexpected write")
+	}
+}
@@ -0,0 +1,22 @@
+package implicit
+
+// Test case for a fmt.Stringer that fmt calls implicitly
+type status int
+
+func (s status) String() string {
+	return "status"
+}
+
+// Test case for an error type whose Error method only tests call
+type failure struct{}
+
+func (f failure) Error() string {
+	return "failure"
+}
+
+// Test case for a method named like json.Marshaler's with another signature
+type version struct{}
+
+func (v version) MarshalJSON() string { // want "identifier \"MarshalJSON\" is only used in test files but is not part of test files"
+	return "1.0"
+}
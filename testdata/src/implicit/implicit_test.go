@@ -0,0 +1,15 @@
+package implicit
+
+import "testing"
+
+func TestMethods(t *testing.T) {
+	if status(0).String() != "status" {
+		t.Error("unexpected status")
+	}
+	if (failure{}).Error() != "failure" {
+		t.Error("unexpected failure")
+	}
+	if (version{}).MarshalJSON() != "1.0" {
+		t.Error("unexpected version")
+	}
+}
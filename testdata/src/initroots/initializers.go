@@ -0,0 +1,27 @@
+package initroots
+
+// Test case for a function only called in a package-level var initializer
+func buildTable() map[string]int {
+	return map[string]int{"default": defaultSize}
+}
+
+// Test case for a constant only referenced in another constant's initializer
+const defaultSize = 8
+
+// Test case for a function only called in a grouped multi-value initializer
+func bounds() (int, int) {
+	return 1, maxSize
+}
+
+const maxSize = defaultSize * 4
+
+var table = buildTable()
+
+var (
+	lower, upper = bounds()
+)
+
+// Size returns the size of the table entry for name, clamped to its bounds
+func Size(name string) int {
+	return min(max(table[name], lower), upper)
+}
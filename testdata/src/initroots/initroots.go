@@ -0,0 +1,27 @@
+package initroots
+
+var registry = map[string]func() string{}
+
+// Test case for a function only called from init
+func register(name string, fn func() string) {
+	registry[name] = fn
+}
+
+// Test case for a function only referenced from init
+func defaultHandler() string {
+	return "default"
+}
+
+// Test case for a function only called from a test
+func reset() { // want "identifier \"reset\" is only used in test files but is not part of test files"
+	registry = map[string]func() string{}
+}
+
+func init() {
+	register("default", defaultHandler)
+}
+
+// Lookup returns the handler registered under name
+func Lookup(name string) func() string {
+	return registry[name]
+}
@@ -0,0 +1,20 @@
+package initroots
+
+import "testing"
+
+func TestRegister(t *testing.T) {
+	defer reset()
+	register("test", defaultHandler)
+	if len(registry) != 2 {
+		t.Error("unexpected registry size")
+	}
+}
+
+func TestBuildTable(t *testing.T) {
+	if buildTable()["default"] != defaultSize {
+		t.Error("unexpected default size")
+	}
+	if lo, hi := bounds(); lo > hi || hi != maxSize {
+		t.Error("unexpected bounds")
+	}
+}
@@ -0,0 +1,66 @@
+package interfaces
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Test case for an io.Writer that only tests pass to fmt.Fprint. Production
+// code names io.Writer, but interfaces of the standard library are named
+// too widely to keep their implementations in use.
+type bufferWriter struct {
+	data []byte
+}
+
+func (w *bufferWriter) Write(p []byte) (int, error) { // want "identifier \"Write\" is only used in test files but is not part of test files"
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+// Report writes a status line to any writer
+func Report(w io.Writer) {
+	fmt.Fprintln(w, "ok")
+}
+
+// Test case for an io.Reader that production code passes to io.ReadFull
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// Zeros returns n zero bytes
+func Zeros(n int) []byte {
+	b := make([]byte, n)
+	io.ReadFull(zeroReader{}, b)
+	return b
+}
+
+var zeros = Zeros(1)
+
+// Test case for an io.Closer whose Close method only tests call, since
+// production code never refers to io.Closer
+type resource struct {
+	closed bool
+}
+
+func (r *resource) Close() error { // want "identifier \"Close\" is only used in test files but is not part of test files"
+	r.closed = true
+	return nil
+}
+
+// Test case for a type that production code passes to sort.Sort, which
+// calls its methods through sort.Interface, while tests also call them
+// directly
+type byLength []string
+
+func (s byLength) Len() int           { return len(s) }
+func (s byLength) Less(i, j int) bool { return len(s[i]) < len(s[j]) }
+func (s byLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// SortByLength sorts words from the shortest to the longest
+func SortByLength(words []string) {
+	sort.Sort(byLength(words))
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	w := &bufferWriter{}
+	fmt.Fprint(w, "start ")
+	if _, err := w.Write([]byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if string(w.data) != "start x" {
+		t.Errorf("unexpected data %q", w.data)
+	}
+}
+
+func TestResource(t *testing.T) {
+	r := &resource{}
+	if err := r.Close(); err != nil || !r.closed {
+		t.Error("resource was not closed")
+	}
+}
+
+func TestByLength(t *testing.T) {
+	words := byLength{"ccc", "a"}
+	if words.Len() != 2 || words.Less(0, 1) {
+		t.Error("unexpected order")
+	}
+	words.Swap(0, 1)
+}
+
+func TestZeros(t *testing.T) {
+	if b := Zeros(2); len(b) != 2 || b[0] != 0 {
+		t.Errorf("unexpected zeros %v", b)
+	}
+}
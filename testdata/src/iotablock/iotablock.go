@@ -0,0 +1,21 @@
+package iotablock
+
+// Level is a logging level
+type Level int
+
+// Test case for a const block where only one entry is only used in tests
+const (
+	levelDebug Level = iota
+	levelInfo        // want "identifier \"levelInfo\" is only used in test files but is not part of test files"
+	levelWarn
+)
+
+// Verbose reports whether the level logs debug output
+func Verbose(l Level) bool {
+	return l == levelDebug
+}
+
+// Loud reports whether the level logs warnings
+func Loud(l Level) bool {
+	return l >= levelWarn
+}
@@ -0,0 +1,9 @@
+package iotablock
+
+import "testing"
+
+func TestLevels(t *testing.T) {
+	if levelDebug != 0 || levelInfo != 1 || levelWarn != 2 {
+		t.Error("unexpected levels")
+	}
+}
@@ -0,0 +1,11 @@
+package library
+
+// Test case for an exported function that is part of the public API
+func Parse(input string) []string { // want "identifier \"Parse\" is only used in test files but is not part of test files"
+	return []string{input}
+}
+
+// Test case for an unexported function only used in tests
+func normalize(input string) string { // want "identifier \"normalize\" is only used in test files but is not part of test files"
+	return input
+}
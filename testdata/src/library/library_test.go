@@ -0,0 +1,12 @@
+package library
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	if len(Parse("a")) != 1 {
+		t.Error("unexpected result")
+	}
+	if normalize("a") != "a" {
+		t.Error("unexpected normalization")
+	}
+}
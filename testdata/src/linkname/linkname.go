@@ -0,0 +1,24 @@
+package linkname
+
+import _ "unsafe" // for go:linkname
+
+// Test case for a function that other packages reach through go:linkname
+//
+//go:linkname fastHash
+func fastHash(b []byte) uint32 {
+	var h uint32
+	for _, c := range b {
+		h = h*31 + uint32(c)
+	}
+	return h
+}
+
+// Test case for a function implemented in assembly
+func add(a, b int) int
+
+// Test case for a linkname directive naming another function
+//
+//go:linkname otherName
+func slowHash(b []byte) uint32 { // want "identifier \"slowHash\" is only used in test files but is not part of test files"
+	return uint32(len(b))
+}
@@ -0,0 +1,9 @@
+package linkname
+
+import "testing"
+
+func TestHashes(t *testing.T) {
+	if fastHash([]byte("a")) == slowHash([]byte("a")) || add(1, 2) != 3 {
+		t.Error("unexpected result")
+	}
+}
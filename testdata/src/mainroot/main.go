@@ -0,0 +1,17 @@
+package main
+
+import "fmt"
+
+// Test case for a function only called from main
+func greeting() string {
+	return "hello"
+}
+
+// Test case for a function only called from a test of a command
+func usage() string { // want "identifier \"usage\" is only used in test files but is not part of test files"
+	return "usage: mainroot"
+}
+
+func main() {
+	fmt.Println(greeting())
+}
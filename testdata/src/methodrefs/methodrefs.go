@@ -0,0 +1,66 @@
+package methodrefs
+
+import "io"
+
+// Counter counts events
+type Counter struct {
+	n int
+}
+
+// Test case for a method only referenced as a method expression
+func (c *Counter) Reset() {
+	c.n = 0
+}
+
+// Test case for a method only referenced as a method value
+func (c *Counter) Handle() {
+	c.n++
+}
+
+// Test case for a method only referenced as a method value in tests
+func (c *Counter) Inspect() int { // want "identifier \"Inspect\" is only used in test files but is not part of test files"
+	return c.n
+}
+
+// Test case for a method named like a method that production code calls
+// through an interface, on a type that doesn't implement the interface
+func (c *Counter) Close() { // want "identifier \"Close\" is only used in test files but is not part of test files"
+	c.n = -1
+}
+
+// Gauge holds a level
+type Gauge struct {
+	level int
+}
+
+// NewGauge returns an empty gauge
+func NewGauge() *Gauge {
+	return &Gauge{}
+}
+
+// Test case for a method named like a method of another type that
+// production code uses
+func (g *Gauge) Reset() { // want "identifier \"Reset\" is only used in test files but is not part of test files"
+	g.level = 0
+}
+
+// Test case for a method only called through an interface
+func (g *Gauge) Close() error {
+	g.level = -1
+	return nil
+}
+
+// Shutdown closes a resource
+func Shutdown(c io.Closer) error {
+	return c.Close()
+}
+
+var resets = []func(*Counter){(*Counter).Reset}
+
+// Run passes the handler of a counter to a callback and resets it
+func Run(c *Counter, register func(func())) {
+	register(c.Handle)
+	for _, reset := range resets {
+		reset(c)
+	}
+}
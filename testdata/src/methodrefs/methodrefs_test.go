@@ -0,0 +1,22 @@
+package methodrefs
+
+import "testing"
+
+func TestCounter(t *testing.T) {
+	c := &Counter{}
+	c.Handle()
+	inspect := c.Inspect
+	if inspect() != 1 {
+		t.Error("event was not counted")
+	}
+	(*Counter).Reset(c)
+	c.Close()
+}
+
+func TestGauge(t *testing.T) {
+	g := &Gauge{level: 1}
+	g.Reset()
+	if g.level != 0 {
+		t.Error("gauge was not reset")
+	}
+}
@@ -0,0 +1,31 @@
+package nested
+
+// Test case for a struct type only used in production as a field of its
+// parent type
+type limits struct {
+	max int
+}
+
+// Test case for a struct type only used in production as an embedded type
+type metadata struct {
+	name string
+}
+
+// Test case for a nested struct type only used in tests
+type override struct { // want "identifier \"override\" is only used in test files but is not part of test files"
+	value int
+}
+
+// Settings groups the settings of a service
+type Settings struct {
+	metadata
+	limits limits
+	nested struct {
+		enabled bool
+	}
+}
+
+// Max returns the configured maximum
+func (s Settings) Max() int {
+	return s.limits.max
+}
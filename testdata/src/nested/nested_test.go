@@ -0,0 +1,12 @@
+package nested
+
+import "testing"
+
+func TestSettings(t *testing.T) {
+	l := limits{max: 3}
+	m := metadata{name: "svc"}
+	o := override{value: 1}
+	if l.max != 3 || m.name != "svc" || o.value != 1 {
+		t.Error("unexpected nested values")
+	}
+}
@@ -1,7 +1,7 @@
 package p
 
 // Main function to use the "false positive" identifiers
-func Main() {
+func Main() { // want Main:"unused function"
 	// Use common function from false_positives.go
 	_ = commonFunction()
 
@@ -10,11 +10,11 @@ type InnerStruct struct {
 }
 
 // Test case for nested methods
-func (o *OuterStruct) outerMethod() string {
+func (o *OuterStruct) outerMethod() string { // want "identifier \"outerMethod\" is only used in test files but is not part of test files"
 	return "outer"
 }
 
-func (i *InnerStruct) innerMethod() string {
+func (i *InnerStruct) innerMethod() string { // want "identifier \"innerMethod\" is only used in test files but is not part of test files"
 	return "inner"
 }
 
@@ -23,6 +23,6 @@ type EmbeddedType struct {
 	string
 }
 
-func (e *EmbeddedType) embeddedMethod() string {
+func (e *EmbeddedType) embeddedMethod() string { // want "identifier \"embeddedMethod\" is only used in test files but is not part of test files"
 	return "embedded"
 }
@@ -0,0 +1,16 @@
+package p
+
+import "reflect"
+
+// Test case for methods only called through reflection in production code
+type ReflectedService struct{}
+
+func (s *ReflectedService) Handle() string {
+	return "handled"
+}
+
+// CallByName invokes a method of ReflectedService by its name
+func CallByName() string { // want CallByName:"unused function"
+	v := reflect.ValueOf(&ReflectedService{})
+	return v.MethodByName("Handle").Call(nil)[0].String()
+}
@@ -0,0 +1,11 @@
+package p
+
+import "testing"
+
+func TestReflection(t *testing.T) {
+	// Test direct usage of a method that production code calls by name
+	s := &ReflectedService{}
+	if s.Handle() != "handled" {
+		t.Error("unexpected handle result")
+	}
+}
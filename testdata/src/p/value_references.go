@@ -0,0 +1,28 @@
+package p
+
+// Test case for functions only referenced as values in production code
+func valueOnlyFunction() string {
+	return "value"
+}
+
+var valueOnlyFunctionRef = valueOnlyFunction
+
+// Test case for functions stored in a map without being called
+func handlerOnlyFunction() string {
+	return "handler"
+}
+
+var handlers = map[string]func() string{
+	"handler": handlerOnlyFunction,
+}
+
+// Test case for methods only referenced as method values in production code
+type ValueHolder struct {
+	name string
+}
+
+func (v *ValueHolder) methodValue() string {
+	return v.name
+}
+
+var holderMethodValue = (&ValueHolder{name: "holder"}).methodValue
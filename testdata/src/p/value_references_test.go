@@ -0,0 +1,21 @@
+package p
+
+import "testing"
+
+func TestValueReferences(t *testing.T) {
+	// Test function usage
+	if valueOnlyFunction() != "value" {
+		t.Error("unexpected value function result")
+	}
+
+	// Test function stored in a map
+	if handlerOnlyFunction() != "handler" {
+		t.Error("unexpected handler function result")
+	}
+
+	// Test method value usage
+	holder := &ValueHolder{name: "test"}
+	if holder.methodValue() != "test" {
+		t.Error("unexpected method value result")
+	}
+}
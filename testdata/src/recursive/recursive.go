@@ -0,0 +1,31 @@
+package recursive
+
+// Test case for mutually recursive types only used in tests
+type Tree struct { // want "identifier \"Tree\" is only used in test files but is not part of test files"
+	Root *Branch
+}
+
+// Branch refers back to its tree
+type Branch struct { // want "identifier \"Branch\" is only used in test files but is not part of test files"
+	Tree     *Tree
+	Children []*Branch
+}
+
+// Test case for a type embedding a pointer to itself
+type Chain struct { // want "identifier \"Chain\" is only used in test files but is not part of test files"
+	*Chain
+	Value int
+}
+
+// Test case for mutually recursive types where production code uses one
+type Node struct {
+	Next *Node
+	Item *Item
+}
+
+// Item is only used in production through Node
+type Item struct {
+	Owner *Node
+}
+
+var head = &Node{}
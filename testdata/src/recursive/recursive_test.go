@@ -0,0 +1,18 @@
+package recursive
+
+import "testing"
+
+func TestTree(t *testing.T) {
+	tree := &Tree{}
+	tree.Root = &Branch{Tree: tree}
+	tree.Root.Children = append(tree.Root.Children, &Branch{Tree: tree})
+	c := Chain{Chain: &Chain{Value: 1}}
+	if c.Chain.Value != 1 {
+		t.Error("unexpected value")
+	}
+}
+
+func TestNode(t *testing.T) {
+	n := &Node{Item: &Item{}}
+	n.Item.Owner = n
+}
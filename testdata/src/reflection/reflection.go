@@ -0,0 +1,29 @@
+package reflection
+
+import "reflect"
+
+// Test case for methods only called through reflection in production code
+type Service struct{}
+
+func (s *Service) Handle() string { // want "identifier \"Handle\" is only used in test files but is not part of test files"
+	return "handled"
+}
+
+// CallByName invokes a method of Service by its name
+func CallByName() string {
+	v := reflect.ValueOf(&Service{})
+	return v.MethodByName("Handle").Call(nil)[0].String()
+}
+
+// Test case for a type only named in production through the pointer-to-type
+// idiom of reflection
+type Plugin struct {
+	Name string
+}
+
+// NewPlugin creates a zero Plugin through reflection
+func NewPlugin() interface{} {
+	return reflect.New(reflect.TypeOf((*Plugin)(nil)).Elem()).Interface()
+}
+
+var defaultPlugin = NewPlugin()
@@ -0,0 +1,17 @@
+package reflection
+
+import "testing"
+
+func TestReflection(t *testing.T) {
+	s := &Service{}
+	if s.Handle() != "handled" {
+		t.Error("unexpected handle result")
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	p := Plugin{Name: "x"}
+	if p.Name != "x" {
+		t.Error("unexpected name")
+	}
+}
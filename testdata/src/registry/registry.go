@@ -0,0 +1,42 @@
+package registry
+
+// Test cases for types without methods that production code only stores in
+// registries built with composite literals
+
+// Registered as the key of a map literal
+type KeyEntry struct{}
+
+// Registered as the value of a map literal
+type ValueEntry struct{}
+
+// Registered as the element of a slice literal
+type ElementEntry struct{}
+
+// Registered by address as the element of a slice literal
+type PointerEntry struct{}
+
+// Registered as the field of a struct literal
+type FieldEntry struct{}
+
+// registration holds a registered value
+type registration struct {
+	entry interface{}
+}
+
+var (
+	byKey   = map[interface{}]string{KeyEntry{}: "key"}
+	byValue = map[string]interface{}{"value": ValueEntry{}}
+	entries = []interface{}{ElementEntry{}, &PointerEntry{}}
+	field   = registration{entry: FieldEntry{}}
+)
+
+// Test cases for the same shapes when only tests build the registries
+type UnregisteredKey struct{} // want "identifier \"UnregisteredKey\" is only used in test files but is not part of test files"
+
+type UnregisteredValue struct{} // want "identifier \"UnregisteredValue\" is only used in test files but is not part of test files"
+
+type UnregisteredElement struct{} // want "identifier \"UnregisteredElement\" is only used in test files but is not part of test files"
+
+type UnregisteredPointer struct{} // want "identifier \"UnregisteredPointer\" is only used in test files but is not part of test files"
+
+type UnregisteredField struct{} // want "identifier \"UnregisteredField\" is only used in test files but is not part of test files"
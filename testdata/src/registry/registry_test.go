@@ -0,0 +1,20 @@
+package registry
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	registered := []interface{}{KeyEntry{}, ValueEntry{}, ElementEntry{}, &PointerEntry{}, FieldEntry{}}
+	if len(registered) != 5 {
+		t.Error("the registered entries are incomplete")
+	}
+
+	registries := []interface{}{
+		map[interface{}]string{UnregisteredKey{}: "key"},
+		map[string]interface{}{"value": UnregisteredValue{}},
+		[]interface{}{UnregisteredElement{}, &UnregisteredPointer{}},
+		registration{entry: UnregisteredField{}},
+	}
+	if len(registries) != 4 {
+		t.Error("the test registries are incomplete")
+	}
+}
@@ -0,0 +1,13 @@
+package shadowing
+
+// Test case for a variable shadowed by the parameter of a production function
+var counter int // want "identifier \"counter\" is only used in test files but is not part of test files"
+
+// Count increments and assigns its parameter, not the package-level counter
+func Count(counter int) int {
+	counter++
+	counter = counter * 2
+	return counter
+}
+
+var total = Count(1)
@@ -0,0 +1,25 @@
+package shadowing
+
+// Test case for a variable that production code only shadows
+var GlobalVariable = "global" // want "identifier \"GlobalVariable\" is only used in test files but is not part of test files"
+
+// Test case for a function that production code only shadows
+func helper() string { // want "identifier \"helper\" is only used in test files but is not part of test files"
+	return "helper"
+}
+
+// Test case for a type that production code only shadows
+type Config struct{} // want "identifier \"Config\" is only used in test files but is not part of test files"
+
+// Describe shadows the package-level names with its parameter and locals
+func Describe(GlobalVariable string) string {
+	helper := func() string { return GlobalVariable }
+	GlobalVariable = helper()
+	{
+		type Config string
+		var c Config = Config(GlobalVariable)
+		return string(c)
+	}
+}
+
+var description = Describe("local")
@@ -0,0 +1,13 @@
+package shadowing
+
+import "testing"
+
+func TestShadowing(t *testing.T) {
+	if GlobalVariable != helper() || Describe("a") != "a" {
+		_ = Config{}
+	}
+}
+
+func TestCount(t *testing.T) {
+	counter = Count(counter)
+}
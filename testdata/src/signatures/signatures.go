@@ -0,0 +1,42 @@
+package signatures
+
+// Test case for a type only named as a result in production code
+type Widget struct {
+	name string
+}
+
+// Test case for a type only named as a parameter in production code
+type options struct {
+	verbose bool
+}
+
+// Test case for a type only instantiated in tests
+type probe struct { // want "identifier \"probe\" is only used in test files but is not part of test files"
+	hits int
+}
+
+// Test case for a type only named as a channel element in production code
+type event struct {
+	id int
+}
+
+// Test case for a type only named as a map key in production code
+type key string
+
+// NewWidget would build a widget once widgets are configurable
+func NewWidget() *Widget {
+	return nil
+}
+
+// Describe describes the given options
+func Describe(opts options) string {
+	return "options"
+}
+
+// Subscribe registers a channel for events
+func Subscribe(ch chan<- event) {}
+
+// Count counts the keys that are set
+func Count(m map[key]bool) int {
+	return len(m)
+}
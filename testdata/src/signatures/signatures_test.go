@@ -0,0 +1,14 @@
+package signatures
+
+import "testing"
+
+func TestTypes(t *testing.T) {
+	w := &Widget{name: "w"}
+	o := options{verbose: true}
+	p := probe{hits: 1}
+	e := event{id: 1}
+	k := key("k")
+	if w.name != "w" || !o.verbose || p.hits != 1 || e.id != 1 || k != "k" {
+		t.Error("unexpected values")
+	}
+}
@@ -0,0 +1,24 @@
+package strlit
+
+// Usage is the help text of a command that dispatches to its handlers
+const Usage = "run start() to begin, then stop() when done"
+
+// Test case for a function mentioned as a call in a production string
+func start() string {
+	return "started"
+}
+
+// Test case for a function mentioned as a call in a production string
+func stop() string {
+	return "stopped"
+}
+
+// Test case for a function whose name only appears inside a longer word
+func restart() string { // want "identifier \"restart\" is only used in test files but is not part of test files"
+	return "restarted"
+}
+
+// Describe returns a description that mentions forcerestart() only
+func Describe() string {
+	return "see forcerestart() for details"
+}
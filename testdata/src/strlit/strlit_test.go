@@ -0,0 +1,9 @@
+package strlit
+
+import "testing"
+
+func TestHandlers(t *testing.T) {
+	if start() != "started" || stop() != "stopped" || restart() != "restarted" {
+		t.Error("unexpected handler result")
+	}
+}
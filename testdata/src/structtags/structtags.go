@@ -0,0 +1,16 @@
+package structtags
+
+// Test case for a validator only named in a struct tag of production code
+func PositiveAmount(v int) bool {
+	return v > 0
+}
+
+// Test case for a validator only named in a struct tag of a test
+func NonEmptyName(v string) bool { // want "identifier \"NonEmptyName\" is only used in test files but is not part of test files"
+	return v != ""
+}
+
+// Payment is validated through its struct tags
+type Payment struct {
+	Amount int `json:"amount" validate:"custom=PositiveAmount"`
+}
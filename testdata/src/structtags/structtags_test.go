@@ -0,0 +1,14 @@
+package structtags
+
+import "testing"
+
+type account struct {
+	Name string `validate:"custom=NonEmptyName"`
+}
+
+func TestValidators(t *testing.T) {
+	if !PositiveAmount(1) || !NonEmptyName("a") {
+		t.Error("unexpected validation result")
+	}
+	_ = account{}
+}
@@ -0,0 +1,24 @@
+package unused
+
+// Test case for a function nothing uses
+func neverCalled() string { // want "identifier \"neverCalled\" is never used"
+	return "never"
+}
+
+// Test case for a function only used in tests
+func onlyTested() string { // want "identifier \"onlyTested\" is only used in test files but is not part of test files"
+	return "tested"
+}
+
+// Test case for an exported function that importers may use
+func Exported() string {
+	return "exported"
+}
+
+func init() {
+	_ = used()
+}
+
+func used() string {
+	return "used"
+}
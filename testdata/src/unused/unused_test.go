@@ -0,0 +1,9 @@
+package unused
+
+import "testing"
+
+func TestOnlyTested(t *testing.T) {
+	if onlyTested() != "tested" {
+		t.Error("unexpected result")
+	}
+}
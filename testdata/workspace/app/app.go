@@ -0,0 +1,16 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/lib"
+)
+
+func main() {
+	fmt.Println(lib.Title(" app "))
+}
+
+// Test case for a function only used by the tests of its module
+func banner() string {
+	return lib.Title("banner")
+}
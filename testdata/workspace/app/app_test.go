@@ -0,0 +1,9 @@
+package main
+
+import "testing"
+
+func TestBanner(t *testing.T) {
+	if banner() != "BANNER" {
+		t.Error("unexpected banner")
+	}
+}
@@ -0,0 +1,21 @@
+package lib
+
+import "strings"
+
+// Title is used by the app module
+func Title(s string) string {
+	return strings.ToUpper(trim(s))
+}
+
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// Test case for a function only used by the tests of its module
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
@@ -0,0 +1,9 @@
+package lib
+
+import "testing"
+
+func TestReverse(t *testing.T) {
+	if reverse("ab") != "ba" {
+		t.Error("unexpected result")
+	}
+}